@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/api" // Need api.RepoRole type
@@ -55,8 +56,8 @@ var roleAddCmd = &cobra.Command{
 		role := api.RepoRole(roleStr) // Convert validated string to type
 
 		// 3. Call API
-		fmt.Printf("Adding role '%s' for user '%s' in repository '%s/%s'...\n", role, userID, orgSlug, repoSlug)
-		err := apiClient.AddRepoRole(orgSlug, repoSlug, userID, role)
+		fmt.Fprintf(os.Stderr, "Adding role '%s' for user '%s' in repository '%s/%s'...\n", role, userID, orgSlug, repoSlug)
+		err := apiClient.AddRepoRole(cmd.Context(), orgSlug, repoSlug, userID, role)
 		if err != nil {
 			return fmt.Errorf("failed to add role: %w", err) // Wrap API error
 		}
@@ -14,6 +14,8 @@ import (
 )
 
 var roleListRepoFlag string // Specific flag for this command
+var roleListLimitFlag int
+var roleListAllFlag bool
 
 var roleListCmd = &cobra.Command{
 	Use:   "list [flags]", // No <repo> arg, use flag or detect
@@ -21,8 +23,11 @@ var roleListCmd = &cobra.Command{
 	Long:  `Displays a list of users and their assigned roles for a specific repository.`,
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := listOptions(roleListLimitFlag, roleListAllFlag)
+		if err != nil {
+			return err
+		}
 		var orgSlug, repoSlug string
-		var err error
 
 		// 1. Determine Repository
 		if roleListRepoFlag != "" {
@@ -34,22 +39,26 @@ var roleListCmd = &cobra.Command{
 			repoSlug = parts[1]
 		} else {
 			// Try to detect from git remote
-			fmt.Println("Attempting to detect repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Attempting to detect repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
 				return fmt.Errorf("could not detect repository. Use the --repo <org>/<repo> flag")
 			}
 		}
-		fmt.Printf("Fetching roles for repository: %s/%s\n", orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Fetching roles for repository: %s/%s\n", orgSlug, repoSlug)
 
 		// 2. Call API
-		roles, err := apiClient.ListRepoRoles(orgSlug, repoSlug)
+		roles, err := apiClient.ListRepoRoles(cmd.Context(), orgSlug, repoSlug, opts)
 		if err != nil {
 			return err // API error (404, 403, etc.)
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(roles)
+		}
+
 		if len(roles) == 0 {
-			fmt.Println("No specific user roles found for this repository.")
+			fmt.Fprintln(os.Stderr, "No specific user roles found for this repository.")
 			return nil
 		}
 
@@ -72,6 +81,8 @@ var roleListCmd = &cobra.Command{
 
 func init() {
 	roleCmd.AddCommand(roleListCmd)
+	supportsStructuredOutput(roleListCmd)
+	addListFlags(roleListCmd, &roleListLimitFlag, &roleListAllFlag)
 	// Add required --repo flag (or make detection mandatory)
 	roleListCmd.Flags().StringVarP(&roleListRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (required for this command)")
 	roleListCmd.MarkFlagRequired("repo") // Make --repo mandatory as detection might not be suitable for permissions
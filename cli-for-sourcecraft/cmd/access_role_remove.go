@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/api" // Need api.RepoRole type
@@ -48,8 +49,8 @@ var roleRemoveCmd = &cobra.Command{
 		role := api.RepoRole(roleStr) // Convert validated string to type
 
 		// 3. Call API
-		fmt.Printf("Removing role '%s' for user '%s' in repository '%s/%s'...\n", role, userID, orgSlug, repoSlug)
-		err := apiClient.RemoveRepoRole(orgSlug, repoSlug, userID, role)
+		fmt.Fprintf(os.Stderr, "Removing role '%s' for user '%s' in repository '%s/%s'...\n", role, userID, orgSlug, repoSlug)
+		err := apiClient.RemoveRepoRole(cmd.Context(), orgSlug, repoSlug, userID, role)
 		if err != nil {
 			return fmt.Errorf("failed to remove role: %w", err) // Wrap API error
 		}
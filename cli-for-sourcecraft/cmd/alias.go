@@ -0,0 +1,261 @@
+// cmd/alias.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// aliasCmd - базовая команда 'src alias'
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Create shortcuts for src commands",
+	Long: `Aliases are stored in the 'aliases' section of config.yaml and expanded
+before the command line is parsed:
+
+  src alias set backend-prs 'pr list --repo ourorg/backend'
+  src alias set run-dev 'workflow run $1 -r develop'
+  src backend-prs --state open     # src pr list --repo ourorg/backend --state open
+  src run-dev main                 # src workflow run main -r develop
+
+$1, $2, ... are replaced by the arguments given to the alias; the remaining
+arguments are appended. An expansion starting with '!' (or set with --shell)
+is run by sh, which receives the arguments as $1, $2, ...:
+
+  src alias set --shell mine 'src pr list --json=slug,title | grep "$1"'`,
+}
+
+// aliasNamePattern — имена алиасов: viper приводит ключи к нижнему регистру
+// и разбивает их по точкам, поэтому допускаются только такие символы.
+var aliasNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// aliasArgPattern matches the positional placeholders $1, $2, ...
+var aliasArgPattern = regexp.MustCompile(`\$(\d+)`)
+
+// loadAliases reads the 'aliases' section of the global config file. It is
+// called before cobra parses the command line, so --config is looked up by
+// hand. Aliases are never read from .sourcecraft/src.yaml: a cloned
+// repository must not be able to define shell commands.
+func loadAliases(args []string) map[string]string {
+	path := configFileFromArgs(args)
+	if path == "" {
+		path = filepath.Join(getConfigDir(), "config.yaml")
+	}
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return nil
+	}
+	return aliasesFromSettings(settings)
+}
+
+func aliasesFromSettings(settings map[string]interface{}) map[string]string {
+	raw, _ := settings["aliases"].(map[string]interface{})
+	aliases := make(map[string]string, len(raw))
+	for name, expansion := range raw {
+		if expansion != nil {
+			aliases[strings.ToLower(name)] = fmt.Sprint(expansion)
+		}
+	}
+	return aliases
+}
+
+// configFileFromArgs returns the value of --config in args, if any.
+func configFileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if value, ok := strings.CutPrefix(arg, "--config="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// splitGlobalFlags splits the flags of the root command given before the
+// command name (src --hostname x <alias>) from the rest of args, so that
+// aliases and extensions are looked up by the command name.
+func splitGlobalFlags(args []string) (globals, rest []string) {
+	flags := rootCmd.PersistentFlags()
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+		if arg == "-" || arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		var flag *pflag.Flag
+		inline := false
+		if name, ok := strings.CutPrefix(arg, "--"); ok {
+			name, _, inline = strings.Cut(name, "=")
+			flag = flags.Lookup(name)
+		} else {
+			flag = flags.ShorthandLookup(arg[1:2])
+			inline = len(arg) > 2
+		}
+		if flag == nil {
+			break // Флаг команды или неизвестный флаг: его разбирает cobra
+		}
+		i++
+		if !inline && flag.NoOptDefVal == "" && i < len(args) {
+			i++ // Значение отдельным аргументом: --hostname x
+		}
+	}
+	return args[:i], args[i:]
+}
+
+// expandAlias expands args[0] if it is an alias. It returns the new argument
+// list, or for a shell alias (expansion starting with '!') the command to run.
+// Built-in commands always win over aliases.
+func expandAlias(aliases map[string]string, args []string) ([]string, *exec.Cmd, error) {
+	if len(args) == 0 || isBuiltinCommand(args[0]) {
+		return args, nil, nil
+	}
+	expansion, ok := aliases[args[0]]
+	if !ok {
+		return args, nil, nil
+	}
+	rest := args[1:]
+
+	if script, ok := strings.CutPrefix(expansion, "!"); ok {
+		shell, err := exec.LookPath("sh")
+		if err != nil {
+			return nil, nil, fmt.Errorf("alias '%s' is a shell alias, but 'sh' was not found in PATH", args[0])
+		}
+		cmd := exec.Command(shell, append([]string{"-c", script, "--"}, rest...)...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return nil, cmd, nil
+	}
+
+	words, err := splitShellWords(expansion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid alias '%s': %w", args[0], err)
+	}
+	used := map[int]bool{}
+	var expanded []string
+	for _, word := range words {
+		var missing int
+		word = aliasArgPattern.ReplaceAllStringFunc(word, func(m string) string {
+			n, _ := strconv.Atoi(m[1:])
+			if n < 1 || n > len(rest) {
+				missing = n
+				return m
+			}
+			used[n] = true
+			return rest[n-1]
+		})
+		if missing > 0 {
+			return nil, nil, fmt.Errorf("alias '%s' needs argument $%d: %s", args[0], missing, expansion)
+		}
+		expanded = append(expanded, word)
+	}
+	for i, arg := range rest {
+		if !used[i+1] {
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, nil, nil
+}
+
+// isBuiltinCommand reports whether name is a top-level command or one of its aliases.
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAliasExpansion checks that a non-shell expansion starts with a src command.
+func validateAliasExpansion(expansion string) error {
+	words, err := splitShellWords(expansion)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("the expansion is empty")
+	}
+	if !isBuiltinCommand(words[0]) {
+		return fmt.Errorf("'%s' is not a src command. Use --shell (or start the expansion with '!') to run other programs", words[0])
+	}
+	return nil
+}
+
+// splitShellWords splits s into words like a POSIX shell: whitespace separates
+// words, single and double quotes group them, a backslash escapes the next
+// character outside single quotes.
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+func sortedAliasNames(aliases map[string]string) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,31 @@
+// cmd/alias_delete.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var aliasDeleteCmd = &cobra.Command{
+	Use:     "delete <name>",
+	Short:   "Delete an alias",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		removed, err := unsetConfigKeys("aliases." + name)
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			return fmt.Errorf("no alias named '%s'. See 'src alias list'", name)
+		}
+		fmt.Printf("Deleted alias '%s'\n", name)
+		return nil
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasDeleteCmd)
+}
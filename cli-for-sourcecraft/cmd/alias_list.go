@@ -0,0 +1,41 @@
+// cmd/alias_list.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List aliases",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases := aliasesFromSettings(viper.AllSettings())
+
+		if outputOpts.Enabled() {
+			return printStructured(aliases)
+		}
+		if len(aliases) == 0 {
+			fmt.Fprintln(os.Stderr, "No aliases. Create one with 'src alias set <name> <expansion>'.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tEXPANSION")
+		for _, name := range sortedAliasNames(aliases) {
+			fmt.Fprintf(w, "%s\t%s\n", name, aliases[name])
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasListCmd)
+	supportsStructuredOutput(aliasListCmd)
+}
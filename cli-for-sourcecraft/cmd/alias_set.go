@@ -0,0 +1,69 @@
+// cmd/alias_set.go
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var aliasSetShellFlag bool
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <expansion>",
+	Short: "Create or change an alias",
+	Long: `Creates an alias: 'src <name> [args]' runs 'src <expansion> [args]'.
+
+The expansion must start with a src command. $1, $2, ... are replaced by the
+alias arguments. With --shell (or an expansion starting with '!') the
+expansion is run by sh instead, for pipes and other programs.
+
+The name must not be a built-in command and may contain lower-case letters,
+digits, '-' and '_'.`,
+	Example: `  src alias set backend-prs 'pr list --repo ourorg/backend'
+  src alias set run-dev 'workflow run $1 -r develop'
+  src alias set --shell open-prs 'src pr list --json=slug,title | grep "$1"'`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, expansion := args[0], strings.TrimSpace(args[1])
+
+		if !aliasNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid alias name '%s': use lower-case letters, digits, '-' and '_'", name)
+		}
+		if isBuiltinCommand(name) {
+			return fmt.Errorf("'%s' is a built-in command and cannot be used as an alias name", name)
+		}
+		if aliasSetShellFlag && !strings.HasPrefix(expansion, "!") {
+			expansion = "!" + expansion
+		}
+		if !strings.HasPrefix(expansion, "!") {
+			// Поддерживается и привычная запись 'src pr list'
+			expansion = strings.TrimSpace(strings.TrimPrefix(expansion, "src "))
+			if err := validateAliasExpansion(expansion); err != nil {
+				return fmt.Errorf("invalid expansion for alias '%s': %w", name, err)
+			}
+		} else if strings.TrimSpace(expansion[1:]) == "" {
+			return fmt.Errorf("invalid expansion for alias '%s': the shell command is empty", name)
+		}
+
+		_, existed := aliasesFromSettings(viper.AllSettings())[name]
+		viper.Set("aliases."+name, expansion)
+		if err := saveConfig(); err != nil {
+			return err
+		}
+		if existed {
+			fmt.Printf("Changed alias '%s': %s\n", name, expansion)
+		} else {
+			fmt.Printf("Added alias '%s': %s\n", name, expansion)
+		}
+		return nil
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+
+	aliasSetCmd.Flags().BoolVarP(&aliasSetShellFlag, "shell", "s", false, "Run the expansion with sh instead of as a src command")
+}
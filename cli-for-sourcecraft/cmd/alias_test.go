@@ -0,0 +1,29 @@
+// cmd/alias_test.go
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitGlobalFlags(t *testing.T) {
+	tests := []struct {
+		args    string
+		globals string
+		rest    string
+	}{
+		{"co 12", "", "co 12"},
+		{"--hostname corp.example co 12", "--hostname corp.example", "co 12"},
+		{"--hostname=corp.example -v deploy --dry-run", "--hostname=corp.example -v", "deploy --dry-run"},
+		{"--config /tmp/c.yaml --timeout 5s --json deploy", "--config /tmp/c.yaml --timeout 5s --json", "deploy"},
+		{"--verbose", "--verbose", ""},
+		{"--repo o/r pr list", "", "--repo o/r pr list"},
+		{"-v -- deploy", "-v", "-- deploy"},
+	}
+	for _, tt := range tests {
+		globals, rest := splitGlobalFlags(strings.Fields(tt.args))
+		if strings.Join(globals, " ") != tt.globals || strings.Join(rest, " ") != tt.rest {
+			t.Errorf("splitGlobalFlags(%q) = %q, %q; want %q, %q", tt.args, globals, rest, tt.globals, tt.rest)
+		}
+	}
+}
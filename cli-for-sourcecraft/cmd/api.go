@@ -0,0 +1,306 @@
+// cmd/api.go
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiFieldFlags   []string
+	apiTypedFlags   []string
+	apiHeaderFlags  []string
+	apiInputFlag    string
+	apiPaginateFlag bool
+	apiIncludeFlag  bool
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api <method> <path>",
+	Short: "Make an authenticated request to the SourceCraft API",
+	Long: `Sends a request to any SourceCraft API endpoint, including ones that have
+no dedicated command yet, and prints the response body.
+
+<path> is relative to the API base URL of the selected host (see --hostname)
+and may include a query string, e.g. 'orgs/my-org/repos?page_size=10'.
+The request uses the same token and retry policy as every other command.
+
+Fields given with -f are sent as a JSON object for POST, PUT, PATCH and DELETE,
+and as query parameters for GET (or when the body comes from --input).
+-f always sends a string. -F converts true, false, null and integers to JSON
+values and reads the value from a file for '@file' ('@-' for stdin).
+
+Examples:
+  src api GET orgs/my-org/repos --paginate
+  src api POST repos/my-org/my-repo/labels -f name=bug -f color=red
+  src api PATCH repos/my-org/my-repo/issues/12 -F weight=3 -F assignee_id=null
+  src api PATCH repos/my-org/my-repo --input settings.json
+  src api GET repos/my-org/my-repo --include --jq .default_branch`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method := strings.ToUpper(args[0])
+		path := args[1]
+
+		switch method {
+		case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		default:
+			return fmt.Errorf("unsupported HTTP method: '%s'", args[0])
+		}
+		if apiPaginateFlag && method != http.MethodGet {
+			return fmt.Errorf("--paginate can only be used with GET requests")
+		}
+
+		header, err := parseAPIHeaders(apiHeaderFlags)
+		if err != nil {
+			return err
+		}
+		fields, err := parseAPIFields(apiFieldFlags, apiTypedFlags)
+		if err != nil {
+			return err
+		}
+
+		var body []byte
+		switch {
+		case apiInputFlag != "":
+			body, err = readAPIInput(apiInputFlag)
+			if err != nil {
+				return err
+			}
+			path = addQueryParams(path, fields)
+		case method == http.MethodGet || method == http.MethodHead:
+			path = addQueryParams(path, fields)
+		case len(fields) > 0:
+			object := map[string]interface{}{}
+			for _, f := range fields {
+				object[f.key] = f.value
+			}
+			body, err = json.Marshal(object)
+			if err != nil {
+				return fmt.Errorf("JSON marshal error: %w", err)
+			}
+		}
+
+		prevToken := ""
+		for {
+			resp, respBody, err := apiClient.Do(cmd.Context(), method, path, header, body)
+			if resp != nil {
+				if printErr := printAPIResponse(resp, respBody); printErr != nil && err == nil {
+					err = printErr
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if !apiPaginateFlag {
+				return nil
+			}
+
+			token, err := nextPageToken(respBody)
+			if err != nil {
+				return err
+			}
+			if token == "" {
+				return nil
+			}
+			if token == prevToken {
+				return fmt.Errorf("API returned the same next_page_token twice for GET %s", path)
+			}
+			prevToken = token
+			path = setQueryParam(path, "page_token", token)
+		}
+	},
+}
+
+// parseAPIHeaders parses -H "Key: Value" flags.
+func parseAPIHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, h := range values {
+		key, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid header '%s'. Expected format: 'Key: Value'", h)
+		}
+		header.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+// apiField is a -f or -F field; value is a string, bool, int64 or nil.
+type apiField struct {
+	key   string
+	value interface{}
+}
+
+// parseAPIFields parses -f (raw) and -F (typed) key=value flags, keeping
+// their order: first all -f, then all -F.
+func parseAPIFields(raw, typed []string) ([]apiField, error) {
+	var fields []apiField
+	for i, f := range append(append([]string{}, raw...), typed...) {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid field '%s'. Expected format: key=value", f)
+		}
+		if i < len(raw) {
+			fields = append(fields, apiField{key, value})
+			continue
+		}
+		typedValue, err := typedFieldValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", key, err)
+		}
+		fields = append(fields, apiField{key, typedValue})
+	}
+	return fields, nil
+}
+
+// typedFieldValue converts a -F value: true/false/null and integers become
+// JSON values, '@file' is replaced with the contents of the file.
+func typedFieldValue(value string) (interface{}, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	if name, ok := strings.CutPrefix(value, "@"); ok {
+		data, err := readAPIInput(name)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	return value, nil
+}
+
+// readAPIInput reads the request body from a file, or from stdin for "-".
+func readAPIInput(name string) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body from stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body from '%s': %w", name, err)
+	}
+	return data, nil
+}
+
+// addQueryParams appends fields to the query string of path; null is sent as
+// an empty value.
+func addQueryParams(path string, fields []apiField) string {
+	if len(fields) == 0 {
+		return path
+	}
+	query := url.Values{}
+	for _, f := range fields {
+		value := ""
+		if f.value != nil {
+			value = fmt.Sprint(f.value)
+		}
+		query.Add(f.key, value)
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + query.Encode()
+}
+
+// setQueryParam sets (or replaces) a single query parameter of path.
+func setQueryParam(path, key, value string) string {
+	base, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	query.Set(key, value)
+	return base + "?" + query.Encode()
+}
+
+// nextPageToken extracts 'next_page_token' from a JSON list response.
+func nextPageToken(body []byte) (string, error) {
+	var page struct {
+		NextPageToken *string `json:"next_page_token"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return "", fmt.Errorf("--paginate: response is not a JSON object: %w", err)
+	}
+	if page.NextPageToken == nil {
+		return "", nil
+	}
+	return *page.NextPageToken, nil
+}
+
+// printAPIResponse prints the status line and headers (with --include) and
+// the body: through --json/--jq/--template if given, indented if it is JSON,
+// as is otherwise.
+func printAPIResponse(resp *http.Response, body []byte) error {
+	if apiIncludeFlag {
+		fmt.Printf("%s %s\n", resp.Proto, resp.Status)
+		var keys []string
+		for key := range resp.Header {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range resp.Header[key] {
+				fmt.Printf("%s: %s\n", key, value)
+			}
+		}
+		fmt.Println()
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	if !json.Valid(body) {
+		_, err := os.Stdout.Write(body)
+		return err
+	}
+	if outputOpts.Enabled() {
+		var data interface{}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return fmt.Errorf("failed to decode response JSON: %w", err)
+		}
+		return printStructured(data)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	indented.WriteByte('\n')
+	_, err := indented.WriteTo(os.Stdout)
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+	supportsStructuredOutput(apiCmd)
+
+	apiCmd.Flags().StringArrayVarP(&apiFieldFlags, "field", "f", nil, "Add a key=value string field to the JSON body (query string for GET)")
+	apiCmd.Flags().StringArrayVarP(&apiTypedFlags, "typed-field", "F", nil, "Add a key=value field with true/false/null/number conversion and @file values")
+	apiCmd.Flags().StringArrayVarP(&apiHeaderFlags, "header", "H", nil, "Add an HTTP request header in 'Key: Value' format")
+	apiCmd.Flags().StringVar(&apiInputFlag, "input", "", "Read the request body from a file ('-' for stdin)")
+	apiCmd.Flags().BoolVar(&apiPaginateFlag, "paginate", false, "Follow next_page_token and print every page (GET only)")
+	apiCmd.Flags().BoolVarP(&apiIncludeFlag, "include", "i", false, "Print the HTTP status line and response headers before the body")
+}
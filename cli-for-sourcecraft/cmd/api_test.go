@@ -0,0 +1,289 @@
+// cmd/api_test.go
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+// apiRequest is a request recorded by the stub server.
+type apiRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+// apiStub records requests and answers them with handle.
+type apiStub struct {
+	mu       sync.Mutex
+	requests []apiRequest
+	handle   func(w http.ResponseWriter, r *http.Request)
+}
+
+func (s *apiStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, apiRequest{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Clone(), string(body)})
+	s.mu.Unlock()
+	s.handle(w, r)
+}
+
+// runAPICommand runs 'src api args...' against stub with a fresh set of
+// flags and returns its standard output.
+func runAPICommand(t *testing.T, stub *apiStub, stdin string, args ...string) (string, error) {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	oldClient, oldStdin, oldStdout := apiClient, os.Stdin, os.Stdout
+	defer func() { apiClient, os.Stdin, os.Stdout = oldClient, oldStdin, oldStdout }()
+	apiClient = api.NewClient(srv.URL, "test-token")
+	apiClient.HTTPClient = srv.Client()
+
+	apiFieldFlags, apiTypedFlags, apiHeaderFlags = nil, nil, nil
+	apiInputFlag, apiPaginateFlag, apiIncludeFlag = "", false, false
+	if err := apiCmd.Flags().Parse(args); err != nil {
+		t.Fatalf("bad flags %v: %v", args, err)
+	}
+
+	if stdin != "" {
+		path := filepath.Join(t.TempDir(), "stdin")
+		if err := os.WriteFile(path, []byte(stdin), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		os.Stdin = f
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	apiCmd.SetContext(context.Background())
+	runErr := apiCmd.RunE(apiCmd, apiCmd.Flags().Args())
+	w.Close()
+	return <-out, runErr
+}
+
+func jsonReply(body string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(body))
+	}
+}
+
+func TestAPICommandFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "description.md")
+	if err := os.WriteFile(file, []byte("from a file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantQuery string
+		wantBody  map[string]interface{} // nil = без тела
+	}{
+		{
+			name:     "raw fields are strings",
+			args:     []string{"POST", "repos/o/r/labels", "-f", "name=bug", "-f", "count=3", "-f", "ok=true"},
+			wantBody: map[string]interface{}{"name": "bug", "count": "3", "ok": "true"},
+		},
+		{
+			name:     "typed fields",
+			args:     []string{"PATCH", "repos/o/r", "-F", "weight=3", "-F", "private=true", "-F", "archived=false", "-F", "milestone=null", "-F", "name=x1"},
+			wantBody: map[string]interface{}{"weight": float64(3), "private": true, "archived": false, "milestone": nil, "name": "x1"},
+		},
+		{
+			name:     "typed field from a file",
+			args:     []string{"POST", "repos/o/r/issues", "-f", "title=t", "-F", "description=@" + file},
+			wantBody: map[string]interface{}{"title": "t", "description": "from a file"},
+		},
+		{
+			name:      "GET sends fields as query",
+			args:      []string{"GET", "orgs/o/repos?visibility=public", "-f", "page_size=5", "-F", "archived=false"},
+			wantQuery: "visibility=public&archived=false&page_size=5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &apiStub{handle: jsonReply(`{}`)}
+			if _, err := runAPICommand(t, stub, "", tt.args...); err != nil {
+				t.Fatalf("src api %v: %v", tt.args, err)
+			}
+			req := stub.requests[0]
+			if req.Method != tt.args[0] || req.Path != "/"+strings.Split(tt.args[1], "?")[0] {
+				t.Errorf("request %s %s, want %s /%s", req.Method, req.Path, tt.args[0], tt.args[1])
+			}
+			if tt.wantQuery != "" && req.Query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", req.Query, tt.wantQuery)
+			}
+			if tt.wantBody == nil {
+				if req.Body != "" {
+					t.Errorf("body = %q, want none", req.Body)
+				}
+				return
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte(req.Body), &got); err != nil {
+				t.Fatalf("body %q is not JSON: %v", req.Body, err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.wantBody)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("body = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestAPICommandInvalidField(t *testing.T) {
+	for _, flag := range []string{"-f", "-F"} {
+		stub := &apiStub{handle: jsonReply(`{}`)}
+		if _, err := runAPICommand(t, stub, "", "POST", "x", flag, "novalue"); err == nil {
+			t.Errorf("%s novalue: want an error", flag)
+		}
+		if len(stub.requests) != 0 {
+			t.Errorf("%s novalue: request sent despite the invalid field", flag)
+		}
+	}
+}
+
+func TestAPICommandInputFromStdin(t *testing.T) {
+	stub := &apiStub{handle: jsonReply(`{"id": "1"}`)}
+	input := `{"title": "from stdin", "labels": [1, 2]}`
+	if _, err := runAPICommand(t, stub, input, "POST", "repos/o/r/issues", "--input", "-", "-f", "draft=1"); err != nil {
+		t.Fatal(err)
+	}
+	req := stub.requests[0]
+	if req.Body != input {
+		t.Errorf("body = %q, want stdin sent as is", req.Body)
+	}
+	// С --input поля уходят в строку запроса
+	if req.Query != "draft=1" {
+		t.Errorf("query = %q, want draft=1", req.Query)
+	}
+}
+
+func TestAPICommandHeaders(t *testing.T) {
+	stub := &apiStub{handle: jsonReply(`{}`)}
+	_, err := runAPICommand(t, stub, "", "POST", "x",
+		"-H", "Idempotency-Key: 3f1c", "-H", "Accept: application/vnd.test+json", "-H", "X-Multi: a", "-H", "X-Multi: b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := stub.requests[0].Header
+	if h.Get("Idempotency-Key") != "3f1c" {
+		t.Errorf("Idempotency-Key = %q, want 3f1c", h.Get("Idempotency-Key"))
+	}
+	if h.Get("Accept") != "application/vnd.test+json" {
+		t.Errorf("Accept = %q, want the -H value to replace the default", h.Get("Accept"))
+	}
+	if got := strings.Join(h.Values("X-Multi"), ","); got != "a,b" {
+		t.Errorf("X-Multi = %q, want a,b", got)
+	}
+	if h.Get("Authorization") != "Bearer test-token" {
+		t.Errorf("Authorization = %q, want the client token", h.Get("Authorization"))
+	}
+
+	if _, err := runAPICommand(t, &apiStub{handle: jsonReply(`{}`)}, "", "GET", "x", "-H", "no colon"); err == nil {
+		t.Error("-H without a colon: want an error")
+	}
+}
+
+func TestAPICommandPaginate(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"repositories": [{"slug": "a"}], "next_page_token": "p2"}`,
+		"p2": `{"repositories": [{"slug": "b"}], "next_page_token": "p3"}`,
+		"p3": `{"repositories": [{"slug": "c"}]}`,
+	}
+	stub := &apiStub{handle: func(w http.ResponseWriter, r *http.Request) {
+		jsonReply(pages[r.URL.Query().Get("page_token")])(w, r)
+	}}
+	out, err := runAPICommand(t, stub, "", "GET", "orgs/o/repos?page_size=1", "--paginate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.requests) != 3 {
+		t.Fatalf("%d requests, want 3", len(stub.requests))
+	}
+	if q := stub.requests[2].Query; q != "page_size=1&page_token=p3" {
+		t.Errorf("last query = %q, want page_size=1&page_token=p3", q)
+	}
+	for _, slug := range []string{`"a"`, `"b"`, `"c"`} {
+		if !strings.Contains(out, slug) {
+			t.Errorf("output does not contain %s:\n%s", slug, out)
+		}
+	}
+
+	// Тот же токен дважды: остановка вместо бесконечного цикла
+	loop := &apiStub{handle: jsonReply(`{"repositories": [], "next_page_token": "same"}`)}
+	if _, err := runAPICommand(t, loop, "", "GET", "x", "--paginate"); err == nil || len(loop.requests) != 2 {
+		t.Errorf("repeated token: %d requests, error %v; want 2 requests and an error", len(loop.requests), err)
+	}
+
+	if _, err := runAPICommand(t, &apiStub{handle: jsonReply(`{}`)}, "", "POST", "x", "--paginate"); err == nil {
+		t.Error("--paginate with POST: want an error")
+	}
+}
+
+func TestAPICommandInclude(t *testing.T) {
+	out, err := runAPICommand(t, &apiStub{handle: jsonReply(`{"id":"1"}`)}, "", "GET", "x", "--include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, body, ok := strings.Cut(out, "\n\n")
+	if !ok {
+		t.Fatalf("no blank line between headers and body:\n%s", out)
+	}
+	if !strings.HasPrefix(head, "HTTP/1.1 200 OK\n") || !strings.Contains(head, "\nX-Request-Id: req-1") || !strings.Contains(head, "\nContent-Type: application/json") {
+		t.Errorf("headers = %q, want the status line and the response headers", head)
+	}
+	if body != "{\n  \"id\": \"1\"\n}\n" {
+		t.Errorf("body = %q, want indented JSON", body)
+	}
+
+	out, err = runAPICommand(t, &apiStub{handle: jsonReply(`{"id":"1"}`)}, "", "GET", "x")
+	if err != nil || strings.Contains(out, "HTTP/1.1") {
+		t.Errorf("without --include: output %q, error %v; want the body only", out, err)
+	}
+}
+
+func TestAPICommandErrorResponse(t *testing.T) {
+	stub := &apiStub{handle: func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "no such repo"}`))
+	}}
+	out, err := runAPICommand(t, stub, "", "GET", "repos/o/missing")
+	if err == nil {
+		t.Fatal("want an error for 404")
+	}
+	// Тело ошибки все равно выводится
+	if !strings.Contains(out, "no such repo") {
+		t.Errorf("output = %q, want the error body", out)
+	}
+}
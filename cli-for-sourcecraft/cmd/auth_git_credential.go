@@ -0,0 +1,166 @@
+// cmd/auth_git_credential.go
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var authGitCredentialCmd = &cobra.Command{
+	Use:   "git-credential <get|store|erase>",
+	Short: "Git credential helper for SourceCraft hosts",
+	Long: `Implements the git credential helper protocol, so that git uses the token
+stored by 'src auth login' for HTTPS clones, fetches and pushes.
+
+git runs it with 'get', 'store' or 'erase' and the request on stdin.
+Only 'get' does something: it answers for hosts that belong to a configured
+SourceCraft host profile (the host itself or any of its subdomains, e.g.
+git.sourcecraft.dev for sourcecraft.dev). If git asks for a specific user,
+only that account's token is returned. Requests over plain http are never
+answered, so the token is not sent in cleartext.
+
+Register it with 'src auth setup-git' rather than calling it directly.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"get", "store", "erase"},
+	Hidden:    true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		request, err := readGitCredentialRequest(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		switch args[0] {
+		case "get":
+		case "store", "erase":
+			// Токены управляются через 'src auth', git их не сохраняет и не стирает
+			return nil
+		default:
+			return fmt.Errorf("unknown git credential operation '%s'. Expected get, store or erase", args[0])
+		}
+
+		// Только https: по http токен ушел бы открытым текстом
+		if request["protocol"] != "https" {
+			return nil
+		}
+		profile := profileForGitHost(request["host"])
+		if profile == nil {
+			return nil
+		}
+
+		// Если git запрашивает конкретного пользователя, отдаем только его токен
+		if username := request["username"]; username != "" && len(profile.Users) > 0 {
+			if !hasAccount(profile, username) {
+				return nil
+			}
+			asUserFlag = username
+		}
+		token, _, err := lookupToken(profile)
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			return nil
+		}
+
+		username := request["username"]
+		if username == "" {
+			username, _ = activeUser(profile)
+		}
+		if username == "" {
+			username = "token"
+		}
+
+		fmt.Printf("protocol=%s\n", request["protocol"])
+		fmt.Printf("host=%s\n", request["host"])
+		fmt.Printf("username=%s\n", username)
+		fmt.Printf("password=%s\n", token)
+		return nil
+	},
+}
+
+// readGitCredentialRequest reads "key=value" lines up to an empty line or EOF.
+// A 'url' attribute is split into protocol, host and path.
+func readGitCredentialRequest(r io.Reader) (map[string]string, error) {
+	request := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		request[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git credential request: %w", err)
+	}
+
+	if raw := request["url"]; raw != "" {
+		if u, err := url.Parse(raw); err == nil {
+			if request["protocol"] == "" {
+				request["protocol"] = u.Scheme
+			}
+			if request["host"] == "" {
+				request["host"] = u.Host
+			}
+			if request["path"] == "" {
+				request["path"] = strings.TrimPrefix(u.Path, "/")
+			}
+		}
+	}
+	return request, nil
+}
+
+// profileForGitHost returns the host profile that gitHost belongs to: the
+// profile host itself, one of its subdomains or the host of its API URL.
+func profileForGitHost(gitHost string) *hostProfile {
+	host := strings.ToLower(gitHost)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return nil
+	}
+
+	hostnames := configuredHostnames()
+	if !containsString(hostnames, defaultHostname) {
+		hostnames = append(hostnames, defaultHostname)
+	}
+	for _, hostname := range hostnames {
+		profile, err := resolveHost(hostname)
+		if err != nil {
+			continue
+		}
+		apiHost := ""
+		if u, err := url.Parse(profile.APIBaseURL); err == nil {
+			apiHost = strings.ToLower(u.Hostname())
+		}
+		if host == hostname || strings.ToLower(gitHost) == hostname || strings.HasSuffix(host, "."+hostname) || host == apiHost {
+			return profile
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	authCmd.AddCommand(authGitCredentialCmd)
+}
@@ -3,71 +3,157 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/zalando/go-keyring"
+	"cli-for-sourcecraft/internal/api"
+	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+var (
+	authLoginUserFlag      string
+	authLoginWithTokenFlag bool
+	authLoginWebFlag       bool
 )
 
 var authLoginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Authentication using a personal access token (PAT)",
-	Long: `Requests your Personal Access Token
-and stores it in a configuration file for subsequent API requests.`,
+	Long: `Requests your Personal Access Token, checks it against the API
+and stores it for subsequent API requests: in the OS keyring or, where
+there is none, in an encrypted file (see 'credential_store' in config.yaml).
+
+The token is typed without echo. For scripts and CI, pass it on stdin:
+
+  echo "$MY_TOKEN" | src auth login --with-token
+
+With --web, src logs in through the browser instead (OAuth 2.0 device
+flow): it shows a one-time code, waits until you approve it and stores a
+short-lived access token together with a refresh token. Expired access
+tokens are then refreshed automatically. The authorization server is
+'oauth_url' of the host profile (default https://<host>).
+
+Each host can have several accounts (e.g. a personal one and a bot).
+Logging in adds the account the token belongs to and makes it active;
+use 'src auth switch' to change the active account and '--as <user>'
+to use another one for a single command.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("Enter your Personal Access Token (PAT): ")
-		token, err := reader.ReadString('\n')
+		profile, err := currentHost()
 		if err != nil {
-			return fmt.Errorf("the token could not be read: %w", err)
+			return err
+		}
+
+		var token, refreshToken string
+		if authLoginWebFlag {
+			if authLoginWithTokenFlag {
+				return fmt.Errorf("--web and --with-token cannot be used together")
+			}
+			tok, err := deviceFlowLogin(cmd.Context(), profile)
+			if err != nil {
+				return err
+			}
+			token, refreshToken = tok.AccessToken, tok.RefreshToken
+		} else {
+			token, err = readToken(profile.Hostname)
+			if err != nil {
+				return err
+			}
 		}
-		token = strings.TrimSpace(token)
 
 		if token == "" {
 			return fmt.Errorf("the token cannot be empty")
 		}
 
-		err = keyring.Set(keyringServiceName, keyringTokenUser, token)
+		// Проверяем токен до сохранения, чтобы не сохранить опечатку.
+		// Без Refresh: refresh-токен в хранилище относится к старому токену
+		client, err := newAPIClient(profile, token)
 		if err != nil {
-			return fmt.Errorf("failed to save the token in OS keyring: %w", err)
+			return err
+		}
+		user, _, err := validateToken(cmd.Context(), client, profile.Hostname)
+		if err != nil {
+			if !errors.Is(err, api.ErrNotFound) {
+				return fmt.Errorf("token validation failed, the token was not saved: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %s does not support GET /me, the token is saved without validation.\n", profile.APIBaseURL)
+		}
+
+		account := authLoginUserFlag
+		if account == "" && user != nil {
+			account = cliutils.DerefString(user.Slug)
+		}
+		if account == "" {
+			return fmt.Errorf("could not determine the account name of the token. Pass it with --user")
 		}
 
-		if viper.IsSet("token") {
-			viper.Set("token", nil)
+		if err := addAccount(profile, account, token); err != nil {
+			return err
+		}
+		// Refresh-токен хранится рядом с токеном доступа; вход по PAT удаляет старый
+		if refreshToken != "" {
+			if err := credStore.Set(refreshKeyringUser(profile.Hostname, account), refreshToken); err != nil {
+				return fmt.Errorf("failed to save the refresh token in %s: %w", credStore.Description(), err)
+			}
+		} else if err := deleteSecret(refreshKeyringUser(profile.Hostname, account)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to remove the old refresh token: %v\n", err)
 		}
 
-		err = viper.WriteConfig()
-		if err != nil {
-			if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-				configDir := getConfigDir()
-				configPath := filepath.Join(configDir, "config.yaml")
-				fmt.Printf("The configuration file is not found, so we create a new one: %s\n", configPath)
-				if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
-					return fmt.Errorf("failed to create configuration directory '%s': %w", configDir, err)
-				}
-				err = viper.SafeWriteConfig()
-				if err != nil {
-					err = viper.WriteConfigAs(configPath)
-					if err != nil {
-						return fmt.Errorf("failed to create and save configuration file '%s': %w", configPath, err)
-					}
-				}
-			} else {
-				return fmt.Errorf("could not save the configuration: %w", err)
+		if err := saveConfig(); err != nil {
+			return err
+		}
+		if viper.InConfig("token") {
+			if _, err := unsetConfigKeys("token"); err != nil {
+				return err
 			}
 		}
 
-		fmt.Println("\nAuthentication was successful. The token is stored in a secure vault (OS keyring).")
+		fmt.Printf("Logged in to %s as %s. The token is stored in the %s.\n", profile.Hostname, account, credStore.Description())
+		if len(profile.Users) > 1 {
+			fmt.Printf("Active account: %s (accounts on this host: %s).\n", account, strings.Join(profile.Users, ", "))
+		}
 		return nil
 	},
 }
 
+// readToken reads the token from stdin: all of it with --with-token, otherwise
+// one line, without echo if stdin is a terminal.
+func readToken(hostname string) (string, error) {
+	if authLoginWithTokenFlag {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("the token could not be read from stdin: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Enter your Personal Access Token (PAT) for %s: ", hostname)
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("the token could not be read: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	token, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !(err == io.EOF && token != "") {
+		return "", fmt.Errorf("the token could not be read: %w", err)
+	}
+	return strings.TrimSpace(token), nil
+}
+
 func init() {
 	authCmd.AddCommand(authLoginCmd)
+	authLoginCmd.Flags().BoolVar(&authLoginWebFlag, "web", false, "Log in through the browser (OAuth device flow) instead of pasting a token")
+	authLoginCmd.Flags().BoolVar(&authLoginWithTokenFlag, "with-token", false, "Read the token from standard input (for scripts and CI)")
+	authLoginCmd.Flags().StringVar(&authLoginUserFlag, "user", "", "Account name to store the token under (default: the user reported by the API)")
 }
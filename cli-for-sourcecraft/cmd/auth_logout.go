@@ -3,53 +3,70 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-
-	"github.com/zalando/go-keyring"
 )
 
+var authLogoutUserFlag string
+
 var authLogoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logging Out (Deleting a Stored Token)",
-	Long:  `Removes the stored personal access token from the configuration file.`,
-	Args:  cobra.NoArgs,
+	Long: `Removes the stored personal access token of the active account (or of
+the account given with --user) from the credential store. If it was the active
+account, the next logged in account of the host becomes active.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := keyring.Delete(keyringServiceName, keyringTokenUser)
+		profile, err := currentHost()
 		if err != nil {
-			if err != keyring.ErrNotFound {
-				fmt.Printf("Warning: Failed to remove token from keyring: %v\n", err)
-			}
+			return err
 		}
 
-		viper.Set("token", nil)
+		account := authLogoutUserFlag
+		if account == "" {
+			account = profile.User
+		}
+		if account != "" && !hasAccount(profile, account) {
+			return fmt.Errorf("account '%s' is not logged in to %s", account, profile.Hostname)
+		}
 
-		configFileUsed := viper.ConfigFileUsed()
-		if configFileUsed == "" {
-			err := viper.ReadInConfig()
-			if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-				fmt.Println("Configuration file not found. The local token (if any) has been deleted.")
-				return nil
+		if account != "" {
+			if err := removeAccount(profile, account); err != nil {
+				return err
 			}
-			configFileUsed = viper.ConfigFileUsed()
 		}
 
-		if configFileUsed != "" {
-			err := viper.WriteConfig()
-			if err != nil {
-				return fmt.Errorf("configuration update failed '%s': %w", configFileUsed, err)
+		// Устаревшая единственная запись удаляется при выходе из активного аккаунта
+		if authLogoutUserFlag == "" {
+			if err := deleteSecret(profile.KeyringUser); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to remove token: %v\n", err)
 			}
-			fmt.Println("The token has been successfully removed from the configuration file and/or OS keyring.")
-			fmt.Println("Path to the configuration file:", configFileUsed)
-		} else {
-			fmt.Println("The token has been removed from OS keyring.")
 		}
 
+		if err := saveConfig(); err != nil {
+			return err
+		}
+		if authLogoutUserFlag == "" && viper.InConfig("token") {
+			if _, err := unsetConfigKeys("token"); err != nil {
+				return err
+			}
+		}
+
+		if account != "" {
+			fmt.Printf("Logged out of %s account '%s'. The token has been removed.\n", profile.Hostname, account)
+		} else {
+			fmt.Printf("The token for %s has been removed from the configuration file and/or credential store.\n", profile.Hostname)
+		}
+		if profile.User != "" {
+			fmt.Printf("Active account: %s\n", profile.User)
+		}
 		return nil
 	},
 }
 
 func init() {
 	authCmd.AddCommand(authLogoutCmd)
+	authLogoutCmd.Flags().StringVar(&authLogoutUserFlag, "user", "", "Account to log out (default: the active account)")
 }
@@ -0,0 +1,66 @@
+// cmd/auth_setup_git.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var authSetupGitCmd = &cobra.Command{
+	Use:   "setup-git",
+	Short: "Configure git to use src as the credential helper for SourceCraft hosts",
+	Long: `Registers 'src auth git-credential' in your global gitconfig for every
+configured host profile (or only for --hostname) and its subdomains, so that
+HTTPS clones, fetches and pushes use the token from 'src auth login' and git
+no longer asks for a password.
+
+Other credential helpers are disabled for these hosts only.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hostnames := configuredHostnames()
+		if hostnameFlag != "" || len(hostnames) == 0 {
+			hostnames = []string{currentHostname()}
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("could not determine the path to src: %w", err)
+		}
+		// git запускает '!'-helper через sh: обратные слэши Windows-пути он съел бы,
+		// поэтому путь всегда с прямыми слэшами и в одинарных кавычках
+		executable = "'" + strings.ReplaceAll(filepath.ToSlash(executable), "'", `'\''`) + "'"
+		helper := "!" + executable + " auth git-credential"
+
+		for _, hostname := range hostnames {
+			for _, pattern := range []string{"https://" + hostname, "https://*." + hostname} {
+				key := "credential." + pattern + ".helper"
+				// Пустое значение сбрасывает helper'ы, заданные выше (например, osxkeychain)
+				if err := runGitConfig("--global", "--replace-all", key, ""); err != nil {
+					return err
+				}
+				if err := runGitConfig("--global", "--add", key, helper); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Configured git to use src for https://%s and its subdomains.\n", hostname)
+		}
+		return nil
+	},
+}
+
+func runGitConfig(args ...string) error {
+	out, err := exec.Command("git", append([]string{"config"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git config %s failed: %w. Output: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func init() {
+	authCmd.AddCommand(authSetupGitCmd)
+}
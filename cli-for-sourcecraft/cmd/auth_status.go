@@ -0,0 +1,239 @@
+// cmd/auth_status.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cli-for-sourcecraft/internal/api"
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// authHostStatus — результат проверки авторизации для одного хоста.
+type authHostStatus struct {
+	Host        string           `json:"host"`
+	Active      bool             `json:"active"`
+	APIBaseURL  string           `json:"api_base_url"`
+	Account     string           `json:"account,omitempty"`
+	Accounts    []string         `json:"accounts,omitempty"`
+	TokenSource string           `json:"token_source,omitempty"`
+	User        *api.CurrentUser `json:"user,omitempty"`
+	Scopes      []string         `json:"scopes,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	Reachable   bool             `json:"reachable"`
+	Error       string           `json:"error,omitempty"`
+
+	token string
+	err   error
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show authentication status for every configured host",
+	Long: `Checks the token of every configured host (or only of --hostname) against
+the API and shows who it belongs to, where it comes from (SOURCECRAFT_TOKEN for
+sourcecraft.dev, SOURCECRAFT_ENTERPRISE_TOKEN for other hosts, the credential
+store or the legacy 'token' key in config.yaml), its scopes and expiry if the
+server reports them, and whether the API is reachable.
+
+Exits with a non-zero code if any host has no token, an invalid token or an
+unreachable API.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hostnames := configuredHostnames()
+		if hostnameFlag != "" || len(hostnames) == 0 {
+			hostnames = []string{currentHostname()}
+		}
+		active := currentHostname()
+
+		var statuses []*authHostStatus
+		failed := &authCheckError{total: len(hostnames)}
+		for _, hostname := range hostnames {
+			st := checkHostAuth(cmd, hostname)
+			st.Active = hostname == active
+			if st.err != nil {
+				st.Error = st.err.Error()
+				if failed.first == nil {
+					failed.first = st.err
+				}
+				failed.failed++
+			}
+			statuses = append(statuses, st)
+		}
+
+		if outputOpts.Enabled() {
+			if err := printStructured(statuses); err != nil {
+				return err
+			}
+		} else {
+			for i, st := range statuses {
+				if i > 0 {
+					fmt.Println()
+				}
+				printAuthStatus(st)
+			}
+		}
+
+		if failed.failed > 0 {
+			return failed
+		}
+		return nil
+	},
+}
+
+// authCheckError is returned by 'auth status' when some hosts failed. The
+// details are already printed, so the message is short; Unwrap keeps the
+// exit code of the first failure (e.g. 3 for an invalid token).
+type authCheckError struct {
+	failed, total int
+	first         error
+}
+
+func (e *authCheckError) Error() string {
+	return fmt.Sprintf("authentication check failed for %d of %d host(s)", e.failed, e.total)
+}
+
+func (e *authCheckError) Unwrap() error {
+	return e.first
+}
+
+// checkHostAuth looks up the token for hostname and calls GET /me with it.
+func checkHostAuth(cmd *cobra.Command, hostname string) *authHostStatus {
+	st := &authHostStatus{Host: hostname}
+
+	profile, err := resolveHost(hostname)
+	if err != nil {
+		st.err = err
+		return st
+	}
+	st.APIBaseURL = profile.APIBaseURL
+	st.Accounts = profile.Users
+
+	token, source, err := lookupToken(profile)
+	if err != nil {
+		st.err = err
+		return st
+	}
+	if token == "" {
+		st.err = fmt.Errorf("not logged in to %s. Run 'src auth login --hostname %s'", hostname, hostname)
+		return st
+	}
+	st.token = token
+	st.TokenSource = source
+	if source == tokenSourceStore {
+		st.Account, _ = activeUser(profile)
+	}
+
+	client, err := newAPIClient(profile, token)
+	if err != nil {
+		st.err = err
+		return st
+	}
+	client.Retry.MaxAttempts = 1
+	// Истекший OAuth-токен обновляется, как в любой другой команде
+	if source == tokenSourceStore {
+		setupTokenRefresh(client, profile)
+	}
+	user, info, err := validateToken(cmd.Context(), client, profile.Hostname)
+	st.token = client.Token
+	if err != nil {
+		st.Reachable = !errors.Is(err, api.ErrNetwork)
+		st.err = err
+		return st
+	}
+	st.Reachable = true
+	st.User = user
+	if info != nil {
+		st.Scopes = info.Scopes
+		st.ExpiresAt = info.ExpiresAt
+	}
+	return st
+}
+
+// validateToken checks the token of client against GET /me. If client.Refresh
+// is set, an expired token is refreshed first and client.Token is the new one.
+func validateToken(ctx context.Context, client *api.Client, hostname string) (*api.CurrentUser, *api.TokenInfo, error) {
+	user, info, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		if errors.Is(err, api.ErrUnauthorized) {
+			return nil, nil, fmt.Errorf("the token for %s is invalid or expired: %w", hostname, err)
+		}
+		return nil, nil, err
+	}
+	return user, info, nil
+}
+
+func printAuthStatus(st *authHostStatus) {
+	title := st.Host
+	if st.Active {
+		title += " (active)"
+	}
+	fmt.Println(title)
+
+	if st.APIBaseURL != "" {
+		reachability := "reachable"
+		if !st.Reachable {
+			reachability = "NOT reachable"
+		}
+		if st.TokenSource == "" && st.err != nil {
+			reachability = "not checked"
+		}
+		fmt.Printf("  API:          %s (%s)\n", st.APIBaseURL, reachability)
+	}
+
+	if st.TokenSource != "" {
+		source := st.TokenSource
+		switch st.TokenSource {
+		case tokenEnvVar, enterpriseTokenEnvVar:
+			source = st.TokenSource + " environment variable"
+		case tokenSourceStore:
+			source = fmt.Sprintf("account '%s' in the %s", st.Account, credStore.Description())
+		case tokenSourceLegacyKeyring:
+			source = "OS keyring (legacy entry, moved to a per-account entry on the next command)"
+		case tokenSourceConfig:
+			source = "config.yaml (plain text, moved to the keyring on the next command)"
+		}
+		fmt.Printf("  Token:        %s\n", maskToken(st.token))
+		fmt.Printf("  Token source: %s\n", source)
+	}
+
+	if len(st.Accounts) > 0 {
+		fmt.Printf("  Accounts:     %s\n", strings.Join(st.Accounts, ", "))
+	}
+
+	if st.User != nil {
+		fmt.Printf("  Logged in as: %s (ID %s)\n", cliutils.DerefString(st.User.Slug), cliutils.DerefString(st.User.ID))
+		if len(st.Scopes) > 0 {
+			fmt.Printf("  Scopes:       %s\n", strings.Join(st.Scopes, ", "))
+		} else {
+			fmt.Println("  Scopes:       not reported by the server")
+		}
+		if st.ExpiresAt != nil {
+			fmt.Printf("  Expires:      %s\n", st.ExpiresAt.Local().Format("2006-01-02 15:04:05 MST"))
+		} else {
+			fmt.Println("  Expires:      not reported by the server")
+		}
+	}
+
+	if st.err != nil {
+		fmt.Printf("  Error:        %v\n", st.err)
+	}
+}
+
+// maskToken shows only the first characters of a token.
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + strings.Repeat("*", 8)
+}
+
+func init() {
+	authCmd.AddCommand(authStatusCmd)
+	supportsStructuredOutput(authStatusCmd)
+}
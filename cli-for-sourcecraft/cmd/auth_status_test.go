@@ -0,0 +1,97 @@
+// cmd/auth_status_test.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/credstore"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// memStore — хранилище учетных данных в памяти.
+type memStore map[string]string
+
+func (m memStore) Get(key string) (string, error) {
+	if v, ok := m[key]; ok {
+		return v, nil
+	}
+	return "", credstore.ErrNotFound
+}
+
+func (m memStore) Set(key, secret string) error {
+	m[key] = secret
+	return nil
+}
+
+func (m memStore) Delete(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func (m memStore) Description() string { return "test store" }
+
+func TestAuthStatusRefreshesExpiredToken(t *testing.T) {
+	const host = "corp.example"
+	var refreshes int
+	oauthSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" || r.FormValue("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "fresh-token", "refresh_token": "refresh-2", "token_type": "Bearer"}`))
+	}))
+	defer oauthSrv.Close()
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "token expired"}`))
+			return
+		}
+		w.Write([]byte(`{"id": "1", "slug": "alice"}`))
+	}))
+	defer apiSrv.Close()
+
+	t.Cleanup(viper.Reset)
+	t.Setenv(tokenEnvVar, "")
+	t.Setenv(enterpriseTokenEnvVar, "")
+	viper.Set("hosts", []interface{}{map[string]interface{}{
+		"host": host, "api_base_url": apiSrv.URL, "oauth_url": oauthSrv.URL,
+		"users": []interface{}{"alice"}, "user": "alice",
+	}})
+	store := memStore{}
+	oldStore := credStore
+	credStore = store
+	t.Cleanup(func() { credStore = oldStore })
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	// Без refresh-токена истекший токен недействителен
+	store[accountKeyringUser(host, "alice")] = "expired-token"
+	st := checkHostAuth(cmd, host)
+	if !errors.Is(st.err, api.ErrUnauthorized) || !st.Reachable {
+		t.Errorf("without a refresh token: error = %v, reachable %v; want an invalid token on a reachable API", st.err, st.Reachable)
+	}
+
+	store[refreshKeyringUser(host, "alice")] = "refresh-1"
+	st = checkHostAuth(cmd, host)
+	if st.err != nil || st.User == nil || *st.User.Slug != "alice" {
+		t.Fatalf("with a refresh token: error = %v, user %+v; want the refreshed token to be valid", st.err, st.User)
+	}
+	if refreshes != 1 || st.token != "fresh-token" {
+		t.Errorf("%d refreshes, shown token %q; want one refresh and the new token", refreshes, st.token)
+	}
+	if store[accountKeyringUser(host, "alice")] != "fresh-token" || store[refreshKeyringUser(host, "alice")] != "refresh-2" {
+		t.Errorf("stored tokens = %v, want the refreshed ones", store)
+	}
+}
@@ -0,0 +1,57 @@
+// cmd/auth_switch.go
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var authSwitchCmd = &cobra.Command{
+	Use:   "switch [<user>]",
+	Short: "Switch the active account of a host",
+	Long: `Makes another logged in account the active one for the host selected with
+--hostname. Without an argument, switches to the next account in the list.
+
+To use another account for a single command, pass '--as <user>' instead.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := currentHost()
+		if err != nil {
+			return err
+		}
+		if len(profile.Users) == 0 {
+			return fmt.Errorf("no accounts are logged in to %s. Run 'src auth login --hostname %s'", profile.Hostname, profile.Hostname)
+		}
+
+		var account string
+		if len(args) == 1 {
+			account = args[0]
+			if !hasAccount(profile, account) {
+				return fmt.Errorf("account '%s' is not logged in to %s. Logged in accounts: %s", account, profile.Hostname, strings.Join(profile.Users, ", "))
+			}
+		} else {
+			if len(profile.Users) == 1 {
+				return fmt.Errorf("only one account is logged in to %s. Add another one with 'src auth login'", profile.Hostname)
+			}
+			account = profile.Users[0]
+			for i, u := range profile.Users {
+				if u == profile.User {
+					account = profile.Users[(i+1)%len(profile.Users)]
+				}
+			}
+		}
+
+		setHostSetting(profile.Hostname, "user", account)
+		if err := saveConfig(); err != nil {
+			return err
+		}
+		fmt.Printf("Switched the active account of %s to %s.\n", profile.Hostname, account)
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authSwitchCmd)
+}
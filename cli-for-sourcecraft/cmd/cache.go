@@ -0,0 +1,18 @@
+// cmd/cache.go
+package cmd
+
+import "github.com/spf13/cobra"
+
+// cacheCmd - базовая команда 'src cache'
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local API response cache",
+	Long: `Manage the on-disk cache of API responses used with --cache.
+
+Run any command with --cache <ttl> (or set 'cache.ttl' in config.yaml) to reuse
+GET responses younger than the TTL and revalidate older ones with ETag.`,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
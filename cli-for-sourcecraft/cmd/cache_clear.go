@@ -0,0 +1,28 @@
+// cmd/cache_clear.go
+package cmd
+
+import (
+	"fmt"
+
+	"cli-for-sourcecraft/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached API responses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := api.ClearCache(httpCacheDir())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cached responses from %s\n", removed, httpCacheDir())
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
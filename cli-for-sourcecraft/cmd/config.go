@@ -1,7 +1,14 @@
 // cmd/config.go
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
 
 // configCmd - базовая команда 'src config'
 var configCmd = &cobra.Command{
@@ -12,3 +19,28 @@ var configCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(configCmd)
 }
+
+// saveConfig writes the current configuration, creating config.yaml in the
+// configuration directory if no file has been loaded yet.
+func saveConfig() error {
+	err := viper.WriteConfig()
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+		return fmt.Errorf("could not save the configuration: %w", err)
+	}
+
+	configDir := getConfigDir()
+	configPath := filepath.Join(configDir, "config.yaml")
+	fmt.Fprintf(os.Stderr, "The configuration file is not found, so we create a new one: %s\n", configPath)
+	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create configuration directory '%s': %w", configDir, err)
+	}
+	if err := viper.SafeWriteConfig(); err != nil {
+		if err := viper.WriteConfigAs(configPath); err != nil {
+			return fmt.Errorf("failed to create and save configuration file '%s': %w", configPath, err)
+		}
+	}
+	return nil
+}
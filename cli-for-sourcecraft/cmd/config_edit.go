@@ -0,0 +1,108 @@
+// cmd/config_edit.go
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+var configEditLocalFlag bool
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in your editor",
+	Long: `Opens config.yaml in $VISUAL, $EDITOR or git's core.editor.
+
+The changes are checked when the editor exits: if the file is not valid YAML
+or a known key has an invalid value, the configuration is left unchanged and
+the edited copy is kept so that you can fix it. Unknown keys only produce a warning.
+
+With --local .sourcecraft/src.yaml of the current repository is edited instead.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configFilePath()
+		if configEditLocalFlag {
+			var err error
+			if path, err = repoConfigWritePath(); err != nil {
+				return err
+			}
+		}
+		original, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read configuration file '%s': %w", path, err)
+		}
+
+		// Редактируется копия: конфигурация меняется только после проверки
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create configuration directory '%s': %w", filepath.Dir(path), err)
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(path), "config-edit-*.yaml")
+		if err != nil {
+			return fmt.Errorf("failed to create a temporary file: %w", err)
+		}
+		tmpPath := tmp.Name()
+		_, err = tmp.Write(original)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to write temporary file '%s': %w", tmpPath, err)
+		}
+
+		if err := runEditor(tmpPath); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read the edited file '%s': %w", tmpPath, err)
+		}
+		if bytes.Equal(edited, original) {
+			os.Remove(tmpPath)
+			fmt.Println("No changes.")
+			return nil
+		}
+
+		settings := map[string]interface{}{}
+		if err := yaml.Unmarshal(edited, &settings); err != nil {
+			return fmt.Errorf("the edited configuration is not valid YAML: %w\nThe configuration is unchanged, your edits are kept in %s", err, tmpPath)
+		}
+		if err := validateConfigSettings(settings); err != nil {
+			return fmt.Errorf("the edited configuration is invalid:\n%w\nThe configuration is unchanged, your edits are kept in %s", err, tmpPath)
+		}
+		for _, key := range unknownConfigKeys(settings) {
+			fmt.Fprintf(os.Stderr, "Warning: unknown key '%s'\n", key)
+		}
+		if configEditLocalFlag {
+			for _, key := range nonRepoConfigKeys(settings) {
+				fmt.Fprintf(os.Stderr, "Warning: '%s' is ignored in %s: only repository keys can be set there\n", key, repoConfigFile)
+			}
+		}
+
+		perm := os.FileMode(0o600)
+		if configEditLocalFlag {
+			// Файл репозитория коммитится вместе с кодом
+			perm = 0o644
+		}
+		err = writeFileAtomic(path, edited, perm)
+		os.Remove(tmpPath)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Configuration saved to", path)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configEditCmd)
+
+	configEditCmd.Flags().BoolVar(&configEditLocalFlag, "local", false, "Edit .sourcecraft/src.yaml of the current repository")
+}
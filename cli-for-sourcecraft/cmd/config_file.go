@@ -0,0 +1,221 @@
+// cmd/config_file.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// configFilePath returns the loaded config.yaml, or the path where a new one
+// is created by saveConfig.
+func configFilePath() string {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+	return filepath.Join(getConfigDir(), "config.yaml")
+}
+
+// readConfigFile parses config.yaml at path. A missing file is an empty config.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file '%s': %w", path, err)
+	}
+	settings := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("configuration file '%s' is not valid YAML: %w", path, err)
+	}
+	return settings, nil
+}
+
+// unsetConfigKeys removes dotted keys (e.g. "cache.ttl") from config.yaml
+// and reloads it. It returns the keys that were actually present.
+//
+// viper.Set(key, nil) cannot be used for this: viper keeps the value from the
+// file and WriteConfig writes it back.
+func unsetConfigKeys(keys ...string) ([]string, error) {
+	path := configFilePath()
+	removed, err := removeFileKeys(path, keys...)
+	if err != nil || len(removed) == 0 {
+		return nil, err
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to reload configuration file '%s': %w", path, err)
+	}
+	return removed, nil
+}
+
+// removeFileKeys removes dotted keys from the YAML file at path. Emptied
+// parent sections are removed as well. It returns the keys that were present.
+func removeFileKeys(path string, keys ...string) ([]string, error) {
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, key := range keys {
+		if deleteNestedKey(settings, strings.Split(strings.ToLower(key), ".")) {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	return removed, writeConfigFile(path, settings, 0o600)
+}
+
+// setFileKey sets a dotted key in the YAML file at path, creating the file
+// with perm and the parent sections as needed. Other keys are left as they are.
+func setFileKey(path, key string, value interface{}, perm os.FileMode) error {
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(strings.ToLower(key), ".")
+	m := settings
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := m[part].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			m[part] = nested
+		}
+		m = nested
+	}
+	m[parts[len(parts)-1]] = value
+	return writeConfigFile(path, settings, perm)
+}
+
+func writeConfigFile(path string, settings map[string]interface{}, perm os.FileMode) error {
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return writeFileAtomic(path, data, perm)
+}
+
+// deleteNestedKey deletes m[path[0]][path[1]]... and prunes empty maps on the way back.
+func deleteNestedKey(m map[string]interface{}, path []string) bool {
+	if len(path) == 1 {
+		if _, ok := m[path[0]]; !ok {
+			return false
+		}
+		delete(m, path[0])
+		return true
+	}
+	nested, ok := m[path[0]].(map[string]interface{})
+	if !ok || !deleteNestedKey(nested, path[1:]) {
+		return false
+	}
+	if len(nested) == 0 {
+		delete(m, path[0])
+	}
+	return true
+}
+
+// writeFileAtomic replaces path with data via a temporary file in the same
+// directory, so that an interrupted write never leaves a truncated config.
+// Existing permissions are kept; new files are created with perm.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	mode := perm
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create configuration directory '%s': %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to write configuration file '%s': %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write configuration file '%s': %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write configuration file '%s': %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to write configuration file '%s': %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace configuration file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// validateConfigSettings checks the values of known keys in a parsed
+// config.yaml, both at the top level and in 'hosts' profiles.
+func validateConfigSettings(settings map[string]interface{}) error {
+	var problems []string
+	check := func(where, name string, value interface{}) {
+		k := lookupConfigKey(name)
+		if k == nil || k.Internal || value == nil {
+			return
+		}
+		if _, err := k.parseValue(fmt.Sprint(value)); err != nil {
+			problems = append(problems, where+err.Error())
+		}
+	}
+
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for key, value := range m {
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", nested)
+				continue
+			}
+			check("", prefix+key, value)
+		}
+	}
+	walk("", settings)
+
+	hosts, _ := settings["hosts"].([]interface{})
+	for _, entry := range hosts {
+		profile, ok := entry.(map[string]interface{})
+		if !ok {
+			problems = append(problems, "every entry of 'hosts' must be a map with a 'host' key")
+			continue
+		}
+		if profile["host"] == nil {
+			problems = append(problems, "an entry of 'hosts' has no 'host' key")
+		}
+		for key, value := range profile {
+			if isHostProfileKey(key) {
+				check(fmt.Sprintf("host '%v': ", profile["host"]), key, value)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// warnUnknownConfigKeys prints a warning for every key of the loaded
+// config.yaml that src does not know, which is usually a typo.
+func warnUnknownConfigKeys() {
+	for _, key := range unknownConfigKeys(viper.AllSettings()) {
+		name := key
+		if i := strings.LastIndex(key, "."); i >= 0 && strings.HasPrefix(key, "hosts[") {
+			name = key[i+1:]
+		}
+		if suggestion := closestConfigKey(name); suggestion != "" {
+			fmt.Fprintf(os.Stderr, "Warning: unknown key '%s' in %s. Did you mean '%s'?\n", key, viper.ConfigFileUsed(), suggestion)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: unknown key '%s' in %s\n", key, viper.ConfigFileUsed())
+		}
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -11,41 +12,51 @@ import (
 var configGetCmd = &cobra.Command{
 	Use:   "get <key>",
 	Short: "Get Configuration Parameter Value",
-	Long:  `Displays the value for the specified key from the configuration file.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Displays the effective value of a configuration key: from the environment,
+the repository or global configuration file, or the built-in default.
+
+For host keys the value effective for the selected host profile
+(see --hostname) is shown, for repository keys the value effective in the
+current repository (see .sourcecraft/src.yaml). 'src config list' shows where each value comes from.
+
+` + configKeysHelp(),
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
-
+		k := lookupConfigKey(key)
+		if k == nil {
+			return unknownKeyError(key)
+		}
 		if key == "token" {
-			fmt.Println("<скрыто>")
+			return fmt.Errorf("tokens are kept in the credential store, not in the configuration. Use 'src auth status' to check them")
+		}
+		if k.Internal {
+			return fmt.Errorf("'%s' is managed by src. Use 'src config list' or 'src config edit'", key)
+		}
+
+		if k.Scope == scopeHost {
+			profile, err := currentHost()
+			if err != nil {
+				return err
+			}
+			fmt.Println(profile.get(key))
 			return nil
 		}
 
-		value := viper.Get(key)
-
-		if !viper.IsSet(key) {
-			configFile := viper.ConfigFileUsed()
-			if configFile == "" {
-				err := viper.ReadInConfig()
-				if _, ok := err.(viper.ConfigFileNotFoundError); ok || err != nil {
-					fmt.Printf("Configuration file not found. The '%s' parameter is not set.\n", key)
-					return nil
-				}
-				configFile = viper.ConfigFileUsed()
-				if !viper.IsSet(key) {
-					fmt.Printf("The '%s' parameter was not found in the configuration.\n", key)
-					fmt.Println("Path to the configuration file:", configFile)
-					return nil
-				}
-				value = viper.Get(key)
+		if k.Scope == scopeRepo {
+			if k.Type == typeList {
+				fmt.Println(strings.Join(repoSettingList(key), ","))
 			} else {
-				fmt.Printf("The '%s' parameter was not found in the configuration.\n", key)
-				fmt.Println("Path to the configuration file:", configFile)
-				return nil
+				fmt.Println(repoSettingString(key))
 			}
+			return nil
 		}
 
-		fmt.Printf("%v\n", value)
+		if viper.IsSet(key) {
+			fmt.Println(viper.GetString(key))
+		} else if k.Default != "" {
+			fmt.Println(k.Default)
+		}
 		return nil
 	},
 }
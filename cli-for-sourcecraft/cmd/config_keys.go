@@ -0,0 +1,263 @@
+// cmd/config_keys.go
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Области действия ключей конфигурации.
+const (
+	scopeGlobal = "global" // Top level of config.yaml only
+	scopeHost   = "host"   // Top level (sourcecraft.dev only) or a 'hosts' profile
+	scopeRepo   = "repo"   // Top level or the repository file .sourcecraft/src.yaml
+)
+
+// Типы значений ключей конфигурации.
+const (
+	typeString   = "string"
+	typeBool     = "bool"
+	typeInt      = "int"
+	typeDuration = "duration"
+	typeURL      = "url"
+	typePath     = "path"
+	typeEnum     = "enum"
+	typeList     = "list" // YAML list or comma-separated string
+)
+
+// configKey describes one known configuration key.
+type configKey struct {
+	Name        string
+	Type        string
+	Default     string   // Shown by 'config list'; "" = not set
+	Scope       string   // scopeGlobal, scopeHost or scopeRepo
+	Values      []string // Allowed values of a typeEnum key
+	Description string
+	Internal    bool // Managed by src itself ('hosts', 'aliases', legacy 'token'); not settable
+}
+
+// configKeys is the registry of every key src reads from config.yaml.
+var configKeys = []configKey{
+	{Name: "default_host", Type: typeString, Default: defaultHostname, Scope: scopeGlobal, Description: "Host profile used when --hostname and SOURCECRAFT_HOST are not set"},
+	{Name: "api_base_url", Type: typeURL, Default: defaultAPIBaseURL, Scope: scopeHost, Description: "SourceCraft API address"},
+	{Name: "organization", Type: typeString, Scope: scopeHost, Description: "Default organization for repository commands"},
+	{Name: "keyring_user", Type: typeString, Scope: scopeHost, Description: "Legacy single-token keyring entry, migrated to per-account entries"},
+	{Name: "proxy", Type: typeString, Scope: scopeHost, Description: "Proxy URL ('direct' ignores HTTPS_PROXY/NO_PROXY)"},
+	{Name: "ca_file", Type: typePath, Scope: scopeHost, Description: "PEM bundle trusted in addition to the system roots"},
+	{Name: "client_cert", Type: typePath, Scope: scopeHost, Description: "PEM client certificate for mutual TLS"},
+	{Name: "client_key", Type: typePath, Scope: scopeHost, Description: "PEM client key for mutual TLS"},
+	{Name: "insecure_skip_verify", Type: typeBool, Default: "false", Scope: scopeHost, Description: "Disable TLS certificate checks (lab environments only)"},
+	{Name: "oauth_url", Type: typeURL, Default: "https://<host>", Scope: scopeHost, Description: "OAuth authorization server for 'auth login --web'"},
+	{Name: "oauth_client_id", Type: typeString, Default: defaultOAuthClientID, Scope: scopeHost, Description: "OAuth client ID for 'auth login --web'"},
+	{Name: "credential_store", Type: typeEnum, Values: []string{"keyring", "file", "env"}, Default: "keyring (file if unavailable)", Scope: scopeGlobal, Description: "Where tokens are stored"},
+	{Name: "cache.ttl", Type: typeDuration, Default: "0", Scope: scopeGlobal, Description: "Reuse cached GET responses younger than this (0 = no cache)"},
+	{Name: "retry.max_attempts", Type: typeInt, Default: "3", Scope: scopeGlobal, Description: "Total attempts per request, 1 disables retries"},
+	{Name: "retry.initial_backoff", Type: typeDuration, Default: "1s", Scope: scopeGlobal, Description: "Pause before the first retry"},
+	{Name: "retry.max_backoff", Type: typeDuration, Default: "30s", Scope: scopeGlobal, Description: "Upper bound of the pause between retries"},
+	{Name: "pr.base", Type: typeString, Scope: scopeRepo, Description: "Target branch of 'pr create' (default: the repository's default branch)"},
+	{Name: "pr.reviewers", Type: typeList, Scope: scopeRepo, Description: "Reviewers (logins or IDs) added by 'pr create'"},
+	{Name: "issue.labels", Type: typeList, Scope: scopeRepo, Description: "Label IDs added by 'issue create'"},
+	{Name: "workflow.default", Type: typeString, Scope: scopeRepo, Description: "Workflow started by 'workflow run' without a name"},
+	{Name: "aliases", Scope: scopeGlobal, Internal: true, Description: "Command aliases, see 'src alias'"},
+	{Name: "hosts", Scope: scopeGlobal, Internal: true, Description: "Host profiles, see --hostname"},
+	{Name: "token", Scope: scopeGlobal, Internal: true, Description: "Legacy plain-text token, moved to the credential store"},
+}
+
+// hostOnlyKeys may appear in a 'hosts' profile but not at the top level.
+var hostOnlyKeys = []string{"host", "users", "user"}
+
+// lookupConfigKey returns the registry entry for name, or nil.
+func lookupConfigKey(name string) *configKey {
+	for i := range configKeys {
+		if configKeys[i].Name == name {
+			return &configKeys[i]
+		}
+	}
+	return nil
+}
+
+// isHostProfileKey reports whether key can be set per host.
+func isHostProfileKey(key string) bool {
+	k := lookupConfigKey(key)
+	return k != nil && k.Scope == scopeHost
+}
+
+// configKeysHelp lists the settable keys for the Long help of config commands.
+func configKeysHelp() string {
+	var b strings.Builder
+	headers := map[string]string{
+		scopeGlobal: "Global keys:\n",
+		scopeHost:   "\nHost keys (top level = sourcecraft.dev, or per host with --hostname):\n",
+		scopeRepo:   "\nRepository keys (top level = your default, or per repository with --local):\n",
+	}
+	for _, scope := range []string{scopeGlobal, scopeHost, scopeRepo} {
+		b.WriteString(headers[scope])
+		for _, k := range configKeys {
+			if k.Scope != scope || k.Internal {
+				continue
+			}
+			typ := k.Type
+			if k.Type == typeEnum {
+				typ = strings.Join(k.Values, "|")
+			}
+			fmt.Fprintf(&b, "  %-22s %-9s %s\n", k.Name, typ, k.Description)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// configEnvVar returns the environment variable that overrides key:
+// the key in upper case with dots replaced by underscores (CACHE_TTL).
+func configEnvVar(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// unknownKeyError reports an unknown key, suggesting the closest known one.
+func unknownKeyError(name string) error {
+	if suggestion := closestConfigKey(name); suggestion != "" {
+		return fmt.Errorf("unknown configuration key '%s'. Did you mean '%s'? See 'src config list'", name, suggestion)
+	}
+	return fmt.Errorf("unknown configuration key '%s'. See 'src config list' for the known keys", name)
+}
+
+// parseValue validates raw for the key and converts it to the value stored in
+// config.yaml (bool and int keys are stored as YAML booleans and numbers).
+func (k *configKey) parseValue(raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	switch k.Type {
+	case typeBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for '%s': expected true or false", raw, k.Name)
+		}
+		return v, nil
+	case typeInt:
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("invalid value '%s' for '%s': expected an integer >= 1", raw, k.Name)
+		}
+		return v, nil
+	case typeDuration:
+		if d, err := time.ParseDuration(raw); err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid value '%s' for '%s': expected a duration such as 500ms, 30s or 5m", raw, k.Name)
+		}
+	case typeURL:
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("invalid value '%s' for '%s': expected an http(s) URL", raw, k.Name)
+		}
+	case typeEnum:
+		for _, v := range k.Values {
+			if strings.EqualFold(raw, v) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid value '%s' for '%s': expected one of %s", raw, k.Name, strings.Join(k.Values, ", "))
+	case typeList:
+		var values []interface{}
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values, nil
+	}
+	return raw, nil
+}
+
+// formatConfigValue prints a parsed value; lists are joined with commas.
+func formatConfigValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// unknownConfigKeys returns the keys of a parsed config.yaml that are not in
+// the registry, as dotted paths ("hosts[src.corp.example].organisation" for profiles).
+func unknownConfigKeys(settings map[string]interface{}) []string {
+	var unknown []string
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for key, value := range m {
+			name := prefix + key
+			if lookupConfigKey(name) != nil {
+				continue
+			}
+			if nested, ok := value.(map[string]interface{}); ok && hasKeyPrefix(name+".") {
+				walk(name+".", nested)
+				continue
+			}
+			unknown = append(unknown, name)
+		}
+	}
+	walk("", settings)
+
+	hosts, _ := settings["hosts"].([]interface{})
+	for _, entry := range hosts {
+		profile, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range profile {
+			if !isHostProfileKey(key) && !containsString(hostOnlyKeys, key) {
+				unknown = append(unknown, fmt.Sprintf("hosts[%v].%s", profile["host"], key))
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func hasKeyPrefix(prefix string) bool {
+	for _, k := range configKeys {
+		if strings.HasPrefix(k.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestConfigKey returns the known key closest to name by edit distance,
+// or "" if none is close enough to be a likely typo.
+func closestConfigKey(name string) string {
+	best, bestDist := "", 4
+	for _, k := range configKeys {
+		if k.Internal {
+			continue
+		}
+		if d := editDistance(name, k.Name); d < bestDist {
+			best, bestDist = k.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,130 @@
+// cmd/config_list.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Источники значения в выводе 'config list'.
+const (
+	originFile    = "file"
+	originRepo    = "repo"
+	originEnv     = "env"
+	originDefault = "default"
+)
+
+// configListEntry is one row of 'src config list'.
+type configListEntry struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Origin      string `json:"origin"`
+	Scope       string `json:"scope"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all configuration keys with their values and origins",
+	Long: `Lists every known configuration key with its effective value and where the
+value comes from:
+
+  file       the configuration file (top level)
+  host <h>   the 'hosts' profile of host <h>
+  repo       .sourcecraft/src.yaml of the current repository
+  env        an environment variable: the key in upper case with '.' replaced
+             by '_', e.g. ORGANIZATION or CACHE_TTL
+  default    the built-in default
+
+Host keys are shown for the selected host profile (see --hostname).
+Command line flags override all of these.`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := currentHost()
+		if err != nil {
+			return err
+		}
+
+		var entries []configListEntry
+		for _, k := range configKeys {
+			if k.Internal {
+				continue
+			}
+			entry := configListEntry{Key: k.Name, Scope: k.Scope, Type: k.Type, Description: k.Description}
+			entry.Value, entry.Origin = configValueOrigin(&k, profile)
+			entries = append(entries, entry)
+		}
+
+		if outputOpts.Enabled() {
+			return printStructured(entries)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tORIGIN")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key, e.Value, e.Origin)
+		}
+		w.Flush()
+		if path := viper.ConfigFileUsed(); path != "" {
+			fmt.Fprintln(os.Stderr, "Configuration file:", path)
+		} else {
+			fmt.Fprintln(os.Stderr, "No configuration file yet. It is created at", configFilePath(), "on the first 'src config set'.")
+		}
+		if repoConfigPath != "" {
+			fmt.Fprintln(os.Stderr, "Repository configuration file:", repoConfigPath)
+		}
+		return nil
+	},
+}
+
+// configValueOrigin returns the effective value of k and where it comes from.
+// Viper gives a non-empty environment variable priority over the file.
+func configValueOrigin(k *configKey, profile *hostProfile) (string, string) {
+	if k.Scope == scopeHost {
+		if v, ok := hostSettings(profile.Hostname)[k.Name]; ok && v != nil && strings.TrimSpace(fmt.Sprint(v)) != "" {
+			return profile.get(k.Name), "host " + profile.Hostname
+		}
+		// Верхний уровень config.yaml относится только к sourcecraft.dev
+		if profile.Hostname != defaultHostname {
+			return profile.get(k.Name), originDefault
+		}
+	}
+
+	if k.Scope == scopeRepo {
+		value, origin := repoSetting(k.Name)
+		if k.Type == typeList {
+			return strings.Join(repoSettingList(k.Name), ","), origin
+		}
+		if value == nil {
+			return k.Default, origin
+		}
+		return fmt.Sprint(value), origin
+	}
+
+	origin := originDefault
+	if os.Getenv(configEnvVar(k.Name)) != "" {
+		origin = originEnv
+	} else if viper.InConfig(k.Name) {
+		origin = originFile
+	}
+
+	if k.Scope == scopeHost {
+		return profile.get(k.Name), origin
+	}
+	if origin == originDefault {
+		return k.Default, origin
+	}
+	return viper.GetString(k.Name), origin
+}
+
+func init() {
+	configCmd.AddCommand(configListCmd)
+	supportsStructuredOutput(configListCmd)
+}
@@ -0,0 +1,69 @@
+// cmd/config_path.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// configPaths is the output of 'src config path'.
+type configPaths struct {
+	ConfigFile     string `json:"config_file"`
+	RepoConfigFile string `json:"repo_config_file,omitempty"`
+	Credentials    string `json:"credentials_file"`
+	ConfigDir      string `json:"config_dir"`
+	CacheDir       string `json:"cache_dir"`
+	DataDir        string `json:"data_dir"`
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show where src keeps its configuration, cache and data",
+	Long: `Shows the resolved locations of the configuration file, the repository
+configuration file (.sourcecraft/src.yaml), the encrypted credentials file and
+the config, cache and data directories.
+
+The directories follow the XDG Base Directory specification: XDG_CONFIG_HOME,
+XDG_CACHE_HOME and XDG_DATA_HOME, each with a 'src' subdirectory. Without them
+~/.config/src, ~/.cache/src and ~/.local/share/src are used.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths := configPaths{
+			ConfigFile:     configFilePath(),
+			RepoConfigFile: repoConfigPath,
+			Credentials:    filepath.Join(getConfigDir(), "credentials.enc"),
+			ConfigDir:      getConfigDir(),
+			CacheDir:       getCacheDir(),
+			DataDir:        getDataDir(),
+		}
+		if outputOpts.Enabled() {
+			return printStructured(paths)
+		}
+
+		missing := func(path string) string {
+			if fileExists(path) {
+				return path
+			}
+			return path + " (not created yet)"
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Configuration file:\t%s\n", missing(paths.ConfigFile))
+		if paths.RepoConfigFile != "" {
+			fmt.Fprintf(w, "Repository configuration:\t%s\n", paths.RepoConfigFile)
+		}
+		fmt.Fprintf(w, "Credentials file:\t%s\n", missing(paths.Credentials))
+		fmt.Fprintf(w, "Config directory:\t%s\n", paths.ConfigDir)
+		fmt.Fprintf(w, "Cache directory:\t%s\n", paths.CacheDir)
+		fmt.Fprintf(w, "Data directory:\t%s\n", paths.DataDir)
+		return w.Flush()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPathCmd)
+	supportsStructuredOutput(configPathCmd)
+}
@@ -0,0 +1,170 @@
+// cmd/config_repo.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// repoConfigFile — файл настроек репозитория относительно корня рабочей копии.
+const repoConfigFile = ".sourcecraft/src.yaml"
+
+// Настройки текущего репозитория; nil, если файла нет.
+var (
+	repoConfig     map[string]interface{}
+	repoConfigPath string
+)
+
+// findRepoConfig walks up from dir to the root of the git working copy and
+// returns the first .sourcecraft/src.yaml on the way ("" if there is none)
+// and the working copy root ("" outside a git repository).
+func findRepoConfig(dir string) (path, gitRoot string) {
+	for {
+		candidate := filepath.Join(dir, repoConfigFile)
+		if path == "" {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				path = candidate
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return path, dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// Вне git-репозитория файл не используется
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// loadRepoConfig reads .sourcecraft/src.yaml of the current repository.
+// Only repository keys are honoured there: the file comes with the code, so
+// it must not be able to redirect tokens to another API or proxy.
+func loadRepoConfig() {
+	repoConfig, repoConfigPath = nil, ""
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	path, _ := findRepoConfig(cwd)
+	if path == "" {
+		return
+	}
+	settings, err := readConfigFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if err := validateConfigSettings(settings); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s:\n%v\n", path, err)
+		return
+	}
+	for _, key := range unknownConfigKeys(settings) {
+		fmt.Fprintf(os.Stderr, "Warning: unknown key '%s' in %s\n", key, path)
+	}
+	for _, key := range nonRepoConfigKeys(settings) {
+		fmt.Fprintf(os.Stderr, "Warning: '%s' is ignored in %s: only repository keys can be set there\n", key, path)
+	}
+	repoConfig, repoConfigPath = settings, path
+}
+
+// nonRepoConfigKeys returns the known keys of settings that are not repository keys.
+func nonRepoConfigKeys(settings map[string]interface{}) []string {
+	var keys []string
+	for _, k := range configKeys {
+		if k.Scope != scopeRepo {
+			if _, ok := nestedValue(settings, k.Name); ok {
+				keys = append(keys, k.Name)
+			}
+		}
+	}
+	return keys
+}
+
+// nestedValue returns settings["a"]["b"] for the key "a.b".
+func nestedValue(settings map[string]interface{}, key string) (interface{}, bool) {
+	parts := strings.Split(key, ".")
+	m := settings
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := m[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		m = nested
+	}
+	v, ok := m[parts[len(parts)-1]]
+	return v, ok && v != nil
+}
+
+// repoSetting returns the value of a repository key and its origin, in the
+// order environment, .sourcecraft/src.yaml, config.yaml. Command line flags
+// are applied by the callers on top of it.
+func repoSetting(key string) (interface{}, string) {
+	if os.Getenv(configEnvVar(key)) != "" {
+		return viper.Get(key), originEnv
+	}
+	if v, ok := nestedValue(repoConfig, key); ok {
+		return v, originRepo
+	}
+	if viper.InConfig(key) {
+		return viper.Get(key), originFile
+	}
+	return nil, originDefault
+}
+
+// repoSettingString returns a repository key as a string ("" if not set).
+func repoSettingString(key string) string {
+	v, _ := repoSetting(key)
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(v))
+}
+
+// repoSettingList returns a list repository key; a string is split on commas.
+func repoSettingList(key string) []string {
+	v, _ := repoSetting(key)
+	var raw []string
+	switch value := v.(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range value {
+			raw = append(raw, fmt.Sprint(item))
+		}
+	case []string:
+		raw = value
+	default:
+		raw = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	var values []string
+	for _, item := range raw {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// repoConfigWritePath returns the repository file to write with --local:
+// the loaded one or a new .sourcecraft/src.yaml in the working copy root.
+func repoConfigWritePath() (string, error) {
+	if repoConfigPath != "" {
+		return repoConfigPath, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("could not determine the current directory: %w", err)
+	}
+	_, gitRoot := findRepoConfig(cwd)
+	if gitRoot == "" {
+		return "", errors.New("--local requires a git repository: run the command inside a working copy")
+	}
+	return filepath.Join(gitRoot, repoConfigFile), nil
+}
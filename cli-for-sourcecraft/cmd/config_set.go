@@ -3,59 +3,89 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var configSetLocalFlag bool
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set the value of the configuration parameter",
-	Long:  `Sets or updates the value for the specified key in the configuration file.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Sets or updates the value for the specified key in the configuration file.
+Only known keys are accepted and the value is checked against the key's type.
+
+Host keys are written into the 'hosts' profile section when --hostname is given:
+
+  src config set api_base_url https://src.corp.example/api/v1 --hostname src.corp.example
+
+Repository keys are written into .sourcecraft/src.yaml of the current
+repository with --local (commit the file to share the defaults with the team):
+
+  src config set pr.base develop --local
+
+` + configKeysHelp(),
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
-		value := args[1]
 
 		if key == "token" {
 			return fmt.Errorf("changing the token via 'config set' is not allowed. Use 'auth login'")
 		}
-
-		viper.Set(key, value)
-
-		err := viper.WriteConfig()
+		k := lookupConfigKey(key)
+		if k == nil {
+			return unknownKeyError(key)
+		}
+		if k.Internal {
+			return fmt.Errorf("'%s' cannot be set directly. Use 'src config set <key> <value> --hostname <host>' or 'src config edit'", key)
+		}
+		if hostnameFlag != "" && k.Scope != scopeHost {
+			return fmt.Errorf("'%s' is not a host key and cannot be set per host. Run the command without --hostname", key)
+		}
+		if configSetLocalFlag && k.Scope != scopeRepo {
+			return fmt.Errorf("'%s' is not a repository key and cannot be set with --local", key)
+		}
+		value, err := k.parseValue(args[1])
 		if err != nil {
-			if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-				configDir := getConfigDir()
-				configPath := filepath.Join(configDir, "config.yaml")
-				fmt.Printf("Configuration file not found, create a new one: %s\n", configPath)
-				if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
-					return fmt.Errorf("failed to create configuration directory '%s': %w", configDir, err)
-				}
-				err = viper.SafeWriteConfig()
-				if err != nil {
-					err = viper.WriteConfigAs(configPath)
-					if err != nil {
-						return fmt.Errorf("failed to create and save configuration file '%s': %w", configPath, err)
-					}
-				}
-			} else {
-				return fmt.Errorf("could not save configuration: %w", err)
+			return err
+		}
+
+		if configSetLocalFlag {
+			path, err := repoConfigWritePath()
+			if err != nil {
+				return err
+			}
+			if err := setFileKey(path, key, value, 0o644); err != nil {
+				return err
 			}
+			fmt.Printf("The '%s' parameter of the repository is set to '%s'\n", key, formatConfigValue(value))
+			fmt.Println("Path to the repository configuration file:", path)
+			return nil
 		}
 
-		fmt.Printf("The '%s' parameter is set to '%s'\n", key, value)
-		if viper.ConfigFileUsed() != "" {
-			fmt.Println("Path to the configuration file:", viper.ConfigFileUsed())
+		hostScoped := hostnameFlag != "" && k.Scope == scopeHost
+		if hostScoped {
+			setHostSetting(normalizeHostname(hostnameFlag), key, value)
 		} else {
-			fmt.Println("Path to the configuration file:", filepath.Join(getConfigDir(), "config.yaml"))
+			viper.Set(key, value)
 		}
+		if err := saveConfig(); err != nil {
+			return err
+		}
+
+		if hostScoped {
+			fmt.Printf("The '%s' parameter for host '%s' is set to '%s'\n", key, normalizeHostname(hostnameFlag), formatConfigValue(value))
+		} else {
+			fmt.Printf("The '%s' parameter is set to '%s'\n", key, formatConfigValue(value))
+		}
+		fmt.Println("Path to the configuration file:", configFilePath())
 		return nil
 	},
 }
 
 func init() {
 	configCmd.AddCommand(configSetCmd)
+
+	configSetCmd.Flags().BoolVar(&configSetLocalFlag, "local", false, "Write a repository key into .sourcecraft/src.yaml of the current repository")
 }
@@ -0,0 +1,89 @@
+// cmd/config_unset.go
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var configUnsetLocalFlag bool
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration parameter",
+	Long: `Removes the key from the configuration file, so that the default applies again.
+
+With --hostname a host key is removed from that host's profile only:
+
+  src config unset proxy --hostname src.corp.example
+
+With --local a repository key is removed from .sourcecraft/src.yaml of the
+current repository.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		k := lookupConfigKey(key)
+		if k == nil {
+			return unknownKeyError(key)
+		}
+		if key == "token" {
+			return fmt.Errorf("use 'src auth logout' to remove the token")
+		}
+		if k.Internal {
+			return fmt.Errorf("'%s' cannot be removed directly. Use 'src config edit'", key)
+		}
+
+		if configUnsetLocalFlag {
+			if k.Scope != scopeRepo {
+				return fmt.Errorf("'%s' is not a repository key and cannot be removed with --local", key)
+			}
+			if repoConfigPath == "" {
+				fmt.Printf("The current repository has no %s\n", repoConfigFile)
+				return nil
+			}
+			removed, err := removeFileKeys(repoConfigPath, key)
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				fmt.Printf("The '%s' parameter is not set in %s\n", key, repoConfigPath)
+				return nil
+			}
+			fmt.Printf("The '%s' parameter has been removed from %s\n", key, repoConfigPath)
+			return nil
+		}
+
+		if hostnameFlag != "" && k.Scope == scopeHost {
+			hostname := normalizeHostname(hostnameFlag)
+			settings := hostSettings(hostname)
+			if _, ok := settings[key]; !ok {
+				fmt.Printf("The '%s' parameter is not set for host '%s'\n", key, hostname)
+				return nil
+			}
+			setHostSetting(hostname, key, nil)
+			if err := saveConfig(); err != nil {
+				return err
+			}
+			fmt.Printf("The '%s' parameter for host '%s' has been removed\n", key, hostname)
+			return nil
+		}
+
+		removed, err := unsetConfigKeys(key)
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			fmt.Printf("The '%s' parameter is not set in %s\n", key, configFilePath())
+			return nil
+		}
+		fmt.Printf("The '%s' parameter has been removed from %s\n", key, configFilePath())
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configUnsetCmd)
+
+	configUnsetCmd.Flags().BoolVar(&configUnsetLocalFlag, "local", false, "Remove a repository key from .sourcecraft/src.yaml of the current repository")
+}
@@ -0,0 +1,173 @@
+// cmd/diff_format.go
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// diffFile is one file of a unified diff.
+type diffFile struct {
+	Path      string `json:"path"`
+	OldPath   string `json:"old_path,omitempty"` // для переименованных файлов
+	Status    string `json:"status"`             // "added", "deleted", "renamed", "modified"
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary,omitempty"`
+}
+
+// parseUnifiedDiff lists the files of a git-style unified diff with the
+// number of added and deleted lines.
+func parseUnifiedDiff(diff string) []diffFile {
+	var files []diffFile
+	var cur *diffFile
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, diffFile{Status: "modified"})
+			cur = &files[len(files)-1]
+			inHunk = false
+			// diff --git a/<old> b/<new>; пути без пробелов, точнее — из ---/+++ и rename
+			if i := strings.Index(line, " b/"); i >= 0 {
+				cur.Path = line[i+3:]
+			}
+		case cur == nil:
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			cur.Additions++
+		case inHunk && strings.HasPrefix(line, "-"):
+			cur.Deletions++
+		case inHunk:
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Status = "deleted"
+		case strings.HasPrefix(line, "rename from "):
+			cur.Status = "renamed"
+			cur.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			cur.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "+++ b/"):
+			cur.Path = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/") && cur.Status == "deleted":
+			cur.Path = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "Binary files "):
+			cur.Binary = true
+		}
+	}
+	return files
+}
+
+// ANSI-цвета для diff
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// colorizeDiff colours a unified diff like 'git diff --color'.
+func colorizeDiff(diff string) string {
+	var b strings.Builder
+	inHunk := false
+	lines := strings.SplitAfter(diff, "\n")
+	for _, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		color := ""
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			inHunk = false
+			color = colorBold
+		case strings.HasPrefix(text, "@@"):
+			inHunk = true
+			color = colorCyan
+		case !inHunk:
+			color = colorBold
+		case strings.HasPrefix(text, "+"):
+			color = colorGreen
+		case strings.HasPrefix(text, "-"):
+			color = colorRed
+		}
+		if color == "" || text == "" {
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(color + text + colorReset + line[len(text):])
+	}
+	return b.String()
+}
+
+// writeDiffStat prints a summary like 'git diff --stat'.
+func writeDiffStat(w io.Writer, files []diffFile, color bool) {
+	const maxBar = 40
+	nameWidth, maxChanges := 0, 0
+	additions, deletions := 0, 0
+	for _, f := range files {
+		nameWidth = max(nameWidth, len(diffFileName(f)))
+		maxChanges = max(maxChanges, f.Additions+f.Deletions)
+		additions += f.Additions
+		deletions += f.Deletions
+	}
+	countWidth := len(strconv.Itoa(maxChanges))
+
+	for _, f := range files {
+		if f.Binary {
+			fmt.Fprintf(w, " %-*s | %*s\n", nameWidth, diffFileName(f), countWidth, "Bin")
+			continue
+		}
+		plus, minus := f.Additions, f.Deletions
+		if maxChanges > maxBar {
+			// Масштабируем, но не теряем ненулевые изменения
+			plus = scaleBar(f.Additions, maxChanges, maxBar)
+			minus = scaleBar(f.Deletions, maxChanges, maxBar)
+		}
+		bar := strings.Repeat("+", plus)
+		minusBar := strings.Repeat("-", minus)
+		if color {
+			if bar != "" {
+				bar = colorGreen + bar + colorReset
+			}
+			if minusBar != "" {
+				minusBar = colorRed + minusBar + colorReset
+			}
+		}
+		line := fmt.Sprintf(" %-*s | %*d %s%s", nameWidth, diffFileName(f), countWidth, f.Additions+f.Deletions, bar, minusBar)
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	summary := fmt.Sprintf(" %d file%s changed", len(files), plural(len(files)))
+	if additions > 0 || deletions == 0 {
+		summary += fmt.Sprintf(", %d insertion%s(+)", additions, plural(additions))
+	}
+	if deletions > 0 || additions == 0 {
+		summary += fmt.Sprintf(", %d deletion%s(-)", deletions, plural(deletions))
+	}
+	fmt.Fprintln(w, summary)
+}
+
+// diffFileName returns "old => new" for renamed files and the path otherwise.
+func diffFileName(f diffFile) string {
+	if f.OldPath != "" && f.OldPath != f.Path {
+		return f.OldPath + " => " + f.Path
+	}
+	return f.Path
+}
+
+func scaleBar(n, total, width int) int {
+	if n == 0 {
+		return 0
+	}
+	return max(1, n*width/total)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
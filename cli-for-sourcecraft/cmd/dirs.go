@@ -0,0 +1,57 @@
+// cmd/dirs.go
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Каталоги src по XDG Base Directory Specification. Без переменных XDG_*
+// используются каталоги в домашней директории на всех платформах.
+//
+//	config  $XDG_CONFIG_HOME/src  (~/.config/src)       config.yaml, credentials.enc
+//	cache   $XDG_CACHE_HOME/src   (~/.cache/src)        HTTP responses, safe to delete
+//	data    $XDG_DATA_HOME/src    (~/.local/share/src)  installed extensions
+
+// xdgDir returns $envVar/src if the variable holds an absolute path
+// (relative ones are ignored, as the specification requires), else ~/<fallback>/src.
+func xdgDir(envVar string, fallback ...string) string {
+	if dir := os.Getenv(envVar); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "src")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(append([]string{os.TempDir(), "src"}, fallback...)...)
+	}
+	return filepath.Join(append(append([]string{home}, fallback...), "src")...)
+}
+
+func getConfigDir() string {
+	dir := xdgDir("XDG_CONFIG_HOME", ".config")
+	// Конфигурация, созданная до поддержки XDG_CONFIG_HOME, остается на месте
+	if legacy := legacyConfigDir(); legacy != "" && legacy != dir && !fileExists(filepath.Join(dir, "config.yaml")) && fileExists(filepath.Join(legacy, "config.yaml")) {
+		return legacy
+	}
+	return dir
+}
+
+func legacyConfigDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "src")
+}
+
+func getCacheDir() string {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+func getDataDir() string {
+	return xdgDir("XDG_DATA_HOME", ".local", "share")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
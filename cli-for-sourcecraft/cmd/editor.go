@@ -0,0 +1,83 @@
+// cmd/editor.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// editorCommand returns the user's editor: $VISUAL, $EDITOR, git's
+// core.editor, or a platform default.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if out, err := exec.Command("git", "config", "core.editor").Output(); err == nil {
+		if editor := strings.TrimSpace(string(out)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// runEditor opens path in the user's editor and waits until it exits.
+// The editor may include arguments, e.g. EDITOR="code --wait".
+func runEditor(path string) error {
+	editor := editorCommand()
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %w", editor, err)
+	}
+	return nil
+}
+
+// editText lets the user write a text in the editor. hint is shown below the
+// text as '#' comment lines, which are removed from the result like in git.
+func editText(initial, hint string) (string, error) {
+	tmp, err := os.CreateTemp("", "src-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	var content strings.Builder
+	content.WriteString(initial)
+	content.WriteString("\n")
+	for _, line := range strings.Split(hint, "\n") {
+		content.WriteString("# " + line + "\n")
+	}
+	if _, err := tmp.WriteString(content.String()); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write '%s': %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write '%s': %w", tmp.Name(), err)
+	}
+
+	if err := runEditor(tmp.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s': %w", tmp.Name(), err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
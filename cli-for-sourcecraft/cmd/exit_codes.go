@@ -0,0 +1,45 @@
+// cmd/exit_codes.go
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+// Коды завершения процесса. Скрипты могут различать классы ошибок по $?.
+const (
+	exitOK          = 0   // Success
+	exitError       = 1   // Any other error
+	exitAuth        = 3   // 401/403: missing, invalid or insufficient token
+	exitNotFound    = 4   // 404: repository, PR, issue etc. does not exist
+	exitRateLimited = 5   // 429 after all retries
+	exitNetwork     = 6   // No HTTP response: DNS, connection, TLS, timeout
+	exitServer      = 7   // 5xx from the API
+	exitTimeout     = 124 // --timeout expired (same code as timeout(1))
+	exitInterrupted = 130 // Ctrl-C (128 + SIGINT)
+)
+
+// exitCodeFor maps an error returned by a command to a process exit code.
+func exitCodeFor(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, api.ErrForbidden):
+		return exitAuth
+	case errors.Is(err, api.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, api.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, api.ErrNetwork):
+		return exitNetwork
+	case errors.Is(err, api.ErrServer):
+		return exitServer
+	}
+	return exitError
+}
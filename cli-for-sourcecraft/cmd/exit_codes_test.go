@@ -0,0 +1,68 @@
+// cmd/exit_codes_test.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+// apiError returns the error of GetPullRequest against a server answering status.
+func apiError(t *testing.T, status int) error {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	client := api.NewClient(srv.URL, "test-token")
+	client.HTTPClient = srv.Client()
+	client.Retry.MaxAttempts = 1
+	_, err := client.GetPullRequest(context.Background(), "org", "repo", "7")
+	if err == nil {
+		t.Fatalf("status %d: GetPullRequest() error = nil", status)
+	}
+	return err
+}
+
+func TestExitCodeFor(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	client := api.NewClient(closed.URL, "test-token")
+	client.HTTPClient = closed.Client()
+	client.Retry.MaxAttempts = 1
+	closed.Close()
+	_, _, networkErr := client.GetCurrentUser(context.Background())
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"401", apiError(t, http.StatusUnauthorized), exitAuth},
+		{"403", apiError(t, http.StatusForbidden), exitAuth},
+		{"404 wrapped by GetPullRequest", apiError(t, http.StatusNotFound), exitNotFound},
+		{"429", apiError(t, http.StatusTooManyRequests), exitRateLimited},
+		{"500", apiError(t, http.StatusInternalServerError), exitServer},
+		{"503", apiError(t, http.StatusServiceUnavailable), exitServer},
+		{"network", networkErr, exitNetwork},
+		{"409", apiError(t, http.StatusConflict), exitError},
+		{"400", apiError(t, http.StatusBadRequest), exitError},
+		{"wrapped again by a command", fmt.Errorf("failed to get PR: %w", apiError(t, http.StatusNotFound)), exitNotFound},
+		{"wrapped network error", fmt.Errorf("failed to list: %w", networkErr), exitNetwork},
+		{"auth status failure", &authCheckError{failed: 1, total: 2, first: apiError(t, http.StatusUnauthorized)}, exitAuth},
+		{"joined errors", errors.Join(errors.New("first"), apiError(t, http.StatusForbidden)), exitAuth},
+		{"plain error", errors.New("bad flag"), exitError},
+		{"timeout", fmt.Errorf("command timed out: %w", context.DeadlineExceeded), exitTimeout},
+		{"interrupt", fmt.Errorf("request: %w", context.Canceled), exitInterrupted},
+	}
+	for _, tt := range tests {
+		if got := exitCodeFor(tt.err); got != tt.want {
+			t.Errorf("%s: exitCodeFor(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
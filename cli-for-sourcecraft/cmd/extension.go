@@ -0,0 +1,226 @@
+// cmd/extension.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"cli-for-sourcecraft/internal/git"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// extensionPrefix — исполняемые файлы расширений называются src-<name>.
+const extensionPrefix = "src-"
+
+// extensionCmd - базовая команда 'src extension'
+var extensionCmd = &cobra.Command{
+	Use:     "extension",
+	Short:   "Manage src extensions",
+	Aliases: []string{"extensions", "ext"},
+	Long: `Extensions are executables named src-<name> that add a 'src <name>' command.
+
+They are looked up in the extensions directory (see 'src config path', data
+directory + /extensions) and then in PATH. Built-in commands and aliases win
+over extensions with the same name.
+
+An extension receives its arguments as they are and these environment variables:
+
+  SRC_HOST          host profile in use (also set as SOURCECRAFT_HOST)
+  SRC_API_BASE_URL  API address of the host
+  SRC_TOKEN         token of the active account ("" if not logged in)
+  SRC_ORGANIZATION  default organization of the host
+  SRC_REPO          <org>/<repo> from git remote 'origin' ("" outside a repository)
+  SRC_EXECUTABLE    path to src, for calling it back
+  SRC_CONFIG        configuration file in use, pass it back with --config
+  SRC_VERBOSE       "1" with --verbose, "" otherwise
+
+Flags of src given before the extension name apply to it: with
+'src --hostname corp.example deploy' SRC_HOST, SRC_API_BASE_URL and SRC_TOKEN
+belong to corp.example.`,
+}
+
+// extension describes an installed or PATH extension.
+type extension struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Source  string `json:"source"` // "installed" (extensions directory), "local" (symlinked directory) or "path"
+	Version string `json:"version,omitempty"`
+}
+
+func extensionsDir() string {
+	return filepath.Join(getDataDir(), "extensions")
+}
+
+// extensionExecutable returns the executable of an extension installed in dir.
+func extensionExecutable(dir, name string) string {
+	path := filepath.Join(dir, extensionPrefix+name)
+	if runtime.GOOS == "windows" {
+		for _, ext := range []string{".exe", ".cmd", ".bat"} {
+			if fileExists(path + ext) {
+				return path + ext
+			}
+		}
+	}
+	return path
+}
+
+// findExtension returns the executable for 'src <name>': an installed
+// extension first, then src-<name> in PATH. "" if there is none.
+func findExtension(name string) string {
+	if !aliasNamePattern.MatchString(name) {
+		return ""
+	}
+	exe := extensionExecutable(filepath.Join(extensionsDir(), extensionPrefix+name), name)
+	if info, err := os.Stat(exe); err == nil && !info.IsDir() {
+		return exe
+	}
+	if path, err := exec.LookPath(extensionPrefix + name); err == nil {
+		return path
+	}
+	return ""
+}
+
+// extensionCommand returns the command that runs args[0] as an extension,
+// or nil if args[0] is a built-in command, a flag or not an extension.
+// globals are the root flags given before the name (--config, --hostname,
+// --verbose, ...); they apply to the extension's environment.
+func extensionCommand(globals, args []string) (*exec.Cmd, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(args[0]) {
+		return nil, nil
+	}
+	exe := findExtension(args[0])
+	if exe == "" {
+		return nil, nil
+	}
+
+	// Cobra не запускается, поэтому флаги и конфигурация разбираются здесь
+	if err := rootCmd.PersistentFlags().Parse(globals); err != nil {
+		return nil, err
+	}
+	initConfig()
+	env, err := extensionEnv()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(exe, args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
+
+// extensionEnv passes the resolved host, token and repository to an extension.
+func extensionEnv() ([]string, error) {
+	profile, err := currentHost()
+	if err != nil {
+		return nil, err
+	}
+	token, _, err := lookupToken(profile)
+	if err != nil {
+		return nil, err
+	}
+	repo := ""
+	if org, name, err := git.GetCurrentRepoOwnerAndNameFromRemote("origin"); err == nil {
+		repo = org + "/" + name
+	}
+	self, _ := os.Executable()
+	verbose := ""
+	if verboseFlag {
+		verbose = "1"
+	}
+
+	return append(gitEnv(),
+		hostEnvVar+"="+profile.Hostname,
+		"SRC_HOST="+profile.Hostname,
+		"SRC_API_BASE_URL="+profile.APIBaseURL,
+		"SRC_TOKEN="+token,
+		"SRC_ORGANIZATION="+profile.Organization,
+		"SRC_REPO="+repo,
+		"SRC_EXECUTABLE="+self,
+		"SRC_CONFIG="+viper.ConfigFileUsed(),
+		"SRC_VERBOSE="+verbose,
+	), nil
+}
+
+// listExtensions returns the installed extensions and the src-* executables in PATH.
+func listExtensions() []extension {
+	seen := map[string]bool{}
+	var result []extension
+
+	entries, _ := os.ReadDir(extensionsDir())
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), extensionPrefix)
+		if !ok || !aliasNamePattern.MatchString(name) {
+			continue
+		}
+		dir := filepath.Join(extensionsDir(), entry.Name())
+		ext := extension{Name: name, Path: extensionExecutable(dir, name), Source: "installed"}
+		if entry.Type()&os.ModeSymlink != 0 {
+			ext.Source = "local"
+			if target, err := filepath.EvalSymlinks(dir); err == nil {
+				ext.Path = extensionExecutable(target, name)
+			}
+		} else {
+			ext.Version = extensionVersion(dir)
+		}
+		seen[name] = true
+		result = append(result, ext)
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), extensionPrefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !ok || entry.IsDir() || seen[name] || !aliasNamePattern.MatchString(name) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if found, err := exec.LookPath(path); err != nil || found == "" {
+				continue
+			}
+			seen[name] = true
+			result = append(result, extension{Name: name, Path: path, Source: "path"})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// extensionVersion returns the short commit of an extension cloned with git.
+func extensionVersion(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// extensionNameFromSource derives the extension name from a git URL or a
+// directory: the last path element without .git, which must start with src-.
+func extensionNameFromSource(source string) (string, error) {
+	base := strings.TrimSuffix(strings.TrimRight(source, "/\\"), ".git")
+	if i := strings.LastIndexAny(base, "/\\:"); i >= 0 {
+		base = base[i+1:]
+	}
+	name, ok := strings.CutPrefix(base, extensionPrefix)
+	if !ok || !aliasNamePattern.MatchString(name) {
+		return "", fmt.Errorf("extension repositories must be named src-<name> with a lower-case name, got '%s'", base)
+	}
+	return name, nil
+}
+
+func init() {
+	rootCmd.AddCommand(extensionCmd)
+}
@@ -0,0 +1,93 @@
+// cmd/extension_install.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var extensionInstallCmd = &cobra.Command{
+	Use:   "install <git-url>|<directory>",
+	Short: "Install an extension from a git repository",
+	Long: `Clones a git repository named src-<name> into the extensions directory.
+The repository must contain an executable src-<name> at its root, which
+becomes 'src <name>'.
+
+A local directory is linked instead of copied, which is handy while
+developing an extension: 'src extension install .'`,
+	Example: `  src extension install https://sourcecraft.dev/ourorg/src-deploy.git
+  src extension install .`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := args[0]
+		local := false
+		if info, err := os.Stat(source); err == nil && info.IsDir() {
+			abs, err := filepath.Abs(source)
+			if err != nil {
+				return fmt.Errorf("could not resolve '%s': %w", source, err)
+			}
+			source, local = abs, true
+		}
+
+		name, err := extensionNameFromSource(source)
+		if err != nil {
+			return err
+		}
+		if isBuiltinCommand(name) {
+			return fmt.Errorf("'%s' is a built-in command, an extension with this name would never run", name)
+		}
+		if _, ok := loadAliases(nil)[name]; ok {
+			fmt.Fprintf(os.Stderr, "Warning: alias '%s' has priority over the extension. Remove it with 'src alias delete %s'\n", name, name)
+		}
+
+		target := filepath.Join(extensionsDir(), extensionPrefix+name)
+		if _, err := os.Lstat(target); err == nil {
+			return fmt.Errorf("extension '%s' is already installed. Use 'src extension upgrade %s' or remove it first", name, name)
+		}
+		if err := os.MkdirAll(extensionsDir(), 0o755); err != nil {
+			return fmt.Errorf("failed to create extensions directory '%s': %w", extensionsDir(), err)
+		}
+
+		if local {
+			if err := os.Symlink(source, target); err != nil {
+				return fmt.Errorf("failed to link '%s': %w", source, err)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Cloning %s...\n", source)
+			clone := exec.Command("git", "clone", "--depth", "1", source, target)
+			clone.Env = gitEnv()
+			clone.Stdout = os.Stderr
+			clone.Stderr = os.Stderr
+			if err := clone.Run(); err != nil {
+				os.RemoveAll(target)
+				return fmt.Errorf("git clone %s failed: %w", source, err)
+			}
+		}
+
+		exe := extensionExecutable(target, name)
+		info, err := os.Stat(exe)
+		if err != nil || info.IsDir() {
+			if !local {
+				os.RemoveAll(target)
+			} else {
+				os.Remove(target)
+			}
+			return fmt.Errorf("the repository has no executable '%s%s' at its root", extensionPrefix, name)
+		}
+		if !strings.HasSuffix(exe, ".exe") && info.Mode()&0o111 == 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %s is not executable. Run 'chmod +x %s'\n", exe, exe)
+		}
+
+		fmt.Printf("Installed extension '%s'. Run it with 'src %s'.\n", name, name)
+		return nil
+	},
+}
+
+func init() {
+	extensionCmd.AddCommand(extensionInstallCmd)
+}
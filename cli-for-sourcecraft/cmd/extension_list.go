@@ -0,0 +1,40 @@
+// cmd/extension_list.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var extensionListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List installed extensions and src-* commands in PATH",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extensions := listExtensions()
+
+		if outputOpts.Enabled() {
+			return printStructured(extensions)
+		}
+		if len(extensions) == 0 {
+			fmt.Fprintln(os.Stderr, "No extensions. Install one with 'src extension install <git-url>'.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSOURCE\tVERSION\tPATH")
+		for _, ext := range extensions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ext.Name, ext.Source, ext.Version, ext.Path)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	extensionCmd.AddCommand(extensionListCmd)
+	supportsStructuredOutput(extensionListCmd)
+}
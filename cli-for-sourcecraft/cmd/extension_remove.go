@@ -0,0 +1,45 @@
+// cmd/extension_remove.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var extensionRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Remove an installed extension",
+	Long:    `Removes an extension from the extensions directory. src-* commands found in PATH are not touched.`,
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !aliasNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid extension name '%s'", name)
+		}
+		target := filepath.Join(extensionsDir(), extensionPrefix+name)
+		info, err := os.Lstat(target)
+		if err != nil {
+			return fmt.Errorf("extension '%s' is not installed. See 'src extension list'", name)
+		}
+
+		// Для локального расширения удаляется только ссылка, а не исходники
+		if info.Mode()&os.ModeSymlink != 0 {
+			err = os.Remove(target)
+		} else {
+			err = os.RemoveAll(target)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove extension '%s': %w", name, err)
+		}
+		fmt.Printf("Removed extension '%s'\n", name)
+		return nil
+	},
+}
+
+func init() {
+	extensionCmd.AddCommand(extensionRemoveCmd)
+}
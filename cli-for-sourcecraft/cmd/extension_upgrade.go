@@ -0,0 +1,73 @@
+// cmd/extension_upgrade.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var extensionUpgradeAllFlag bool
+
+var extensionUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [<name>] [--all]",
+	Short: "Upgrade installed extensions",
+	Long: `Pulls the latest commit of an extension installed from git (fast-forward only).
+Linked local extensions and src-* commands in PATH are skipped.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !extensionUpgradeAllFlag {
+			return fmt.Errorf("specify an extension name or --all")
+		}
+		if len(args) > 0 && extensionUpgradeAllFlag {
+			return fmt.Errorf("specify either an extension name or --all, not both")
+		}
+
+		var targets []extension
+		for _, ext := range listExtensions() {
+			if ext.Source == "path" || (len(args) > 0 && ext.Name != args[0]) {
+				continue
+			}
+			targets = append(targets, ext)
+		}
+		if len(args) > 0 && len(targets) == 0 {
+			return fmt.Errorf("extension '%s' is not installed. See 'src extension list'", args[0])
+		}
+
+		failed := 0
+		for _, ext := range targets {
+			if ext.Source == "local" {
+				fmt.Fprintf(os.Stderr, "%s: linked from a local directory, skipping\n", ext.Name)
+				continue
+			}
+			dir := filepath.Join(extensionsDir(), extensionPrefix+ext.Name)
+			pull := exec.Command("git", "-C", dir, "pull", "--ff-only", "--quiet")
+			pull.Env = gitEnv()
+			pull.Stdout = os.Stderr
+			pull.Stderr = os.Stderr
+			if err := pull.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: upgrade failed: %v\n", ext.Name, err)
+				failed++
+				continue
+			}
+			if version := extensionVersion(dir); version == ext.Version {
+				fmt.Printf("%s: already up to date (%s)\n", ext.Name, version)
+			} else {
+				fmt.Printf("%s: upgraded %s -> %s\n", ext.Name, ext.Version, version)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d extensions failed to upgrade", failed, len(targets))
+		}
+		return nil
+	},
+}
+
+func init() {
+	extensionCmd.AddCommand(extensionUpgradeCmd)
+
+	extensionUpgradeCmd.Flags().BoolVar(&extensionUpgradeAllFlag, "all", false, "Upgrade all installed extensions")
+}
@@ -72,7 +72,7 @@ exit 0 # Allow push
 			return fmt.Errorf("internal error: script content not defined for %s", hookType)
 		}
 
-		fmt.Printf("Writing hook script to: %s\n", hookFilePath)
+		fmt.Fprintf(os.Stderr, "Writing hook script to: %s\n", hookFilePath)
 		err = os.WriteFile(hookFilePath, []byte(scriptContent), 0755)
 		if err != nil {
 			return fmt.Errorf("failed to write hook script '%s': %w", hookFilePath, err)
@@ -81,7 +81,7 @@ exit 0 # Allow push
 		if runtime.GOOS != "windows" {
 			err = os.Chmod(hookFilePath, 0755)
 			if err != nil {
-				fmt.Printf("Warning: failed to make hook script executable '%s': %v\n", hookFilePath, err)
+				fmt.Fprintf(os.Stderr, "Warning: failed to make hook script executable '%s': %v\n", hookFilePath, err)
 			}
 		}
 
@@ -0,0 +1,262 @@
+// cmd/hosts.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultHostname — хост, который используется, если профиль не выбран явно.
+const defaultHostname = "sourcecraft.dev"
+
+// defaultAPIBaseURL — адрес API для defaultHostname.
+const defaultAPIBaseURL = "https://api.sourcecraft.tech"
+
+// hostEnvVar selects the host profile when --hostname is not given.
+const hostEnvVar = "SOURCECRAFT_HOST"
+
+var hostnameFlag string
+
+// hostProfile — настройки одного хоста SourceCraft из секции 'hosts' в config.yaml:
+//
+//	default_host: sourcecraft.dev
+//	hosts:
+//	  - host: sourcecraft.dev
+//	    organization: my-org
+//	  - host: src.corp.example
+//	    api_base_url: https://src.corp.example/api/v1
+//	    organization: platform
+//	    ca_file: ~/certs/corp-root.pem
+//	    users: [alice, deploy-bot]
+//	    user: alice
+type hostProfile struct {
+	Hostname     string
+	APIBaseURL   string
+	Organization string
+	KeyringUser  string
+
+	// Сетевые настройки (см. network.go)
+	Proxy              string
+	CAFile             string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+
+	// Сервер авторизации для 'auth login --web' (см. oauth.go)
+	OAuthURL      string
+	OAuthClientID string
+
+	// Аккаунты, вошедшие через 'auth login', и активный из них (см. keyring.go)
+	Users []string
+	User  string
+}
+
+// currentHostname returns the host chosen via --hostname, SOURCECRAFT_HOST,
+// the 'default_host' config key or, if exactly one profile is configured, that profile.
+func currentHostname() string {
+	if hostnameFlag != "" {
+		return normalizeHostname(hostnameFlag)
+	}
+	if env := os.Getenv(hostEnvVar); env != "" {
+		return normalizeHostname(env)
+	}
+	if def := viper.GetString("default_host"); def != "" {
+		return normalizeHostname(def)
+	}
+	if names := configuredHostnames(); len(names) == 1 {
+		return names[0]
+	}
+	return defaultHostname
+}
+
+// currentHost resolves the profile for currentHostname.
+func currentHost() (*hostProfile, error) {
+	return resolveHost(currentHostname())
+}
+
+// resolveHost builds the profile for hostname: values from 'hosts.<hostname>'
+// win over top-level keys, which win over the built-in defaults. Top-level keys
+// belong to sourcecraft.dev only: other hosts get nothing from them, so that
+// its keyring_user, ca_file or api_base_url never apply to another server.
+func resolveHost(hostname string) (*hostProfile, error) {
+	hostname = normalizeHostname(hostname)
+	settings := hostSettings(hostname)
+	if settings == nil && hostname != defaultHostname {
+		return nil, fmt.Errorf("host '%s' is not configured. Add it with 'src config set api_base_url <url> --hostname %s'", hostname, hostname)
+	}
+
+	lookup := func(key string) string {
+		if v, ok := settings[key]; ok && v != nil {
+			if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+				return s
+			}
+		}
+		if hostname != defaultHostname {
+			return ""
+		}
+		return viper.GetString(key)
+	}
+
+	profile := &hostProfile{
+		Hostname:     hostname,
+		APIBaseURL:   strings.TrimSuffix(lookup("api_base_url"), "/"),
+		Organization: lookup("organization"),
+		KeyringUser:  lookup("keyring_user"),
+
+		Proxy:      lookup("proxy"),
+		CAFile:     expandHome(lookup("ca_file")),
+		ClientCert: expandHome(lookup("client_cert")),
+		ClientKey:  expandHome(lookup("client_key")),
+
+		OAuthURL:      strings.TrimSuffix(lookup("oauth_url"), "/"),
+		OAuthClientID: lookup("oauth_client_id"),
+	}
+	if users, ok := settings["users"].([]interface{}); ok {
+		for _, u := range users {
+			if name := strings.TrimSpace(fmt.Sprint(u)); name != "" {
+				profile.Users = append(profile.Users, name)
+			}
+		}
+	}
+	if u, ok := settings["user"]; ok && u != nil {
+		profile.User = strings.TrimSpace(fmt.Sprint(u))
+	}
+	if v := lookup("insecure_skip_verify"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'insecure_skip_verify' value '%s' for host '%s': expected true or false", v, hostname)
+		}
+		profile.InsecureSkipVerify = insecure
+	}
+
+	if profile.APIBaseURL == "" {
+		if hostname != defaultHostname {
+			return nil, fmt.Errorf("no 'api_base_url' configured for host '%s'. Use 'src config set api_base_url <url> --hostname %s'", hostname, hostname)
+		}
+		profile.APIBaseURL = defaultAPIBaseURL
+	}
+	if profile.KeyringUser == "" {
+		profile.KeyringUser = keyringTokenUser
+		if hostname != defaultHostname {
+			profile.KeyringUser = keyringTokenUser + "@" + hostname
+		}
+	}
+	if profile.OAuthURL == "" {
+		profile.OAuthURL = "https://" + hostname
+	}
+	if profile.OAuthClientID == "" {
+		profile.OAuthClientID = defaultOAuthClientID
+	}
+	return profile, nil
+}
+
+// get returns the profile value for a host key (see configKeys).
+func (p *hostProfile) get(key string) string {
+	switch key {
+	case "api_base_url":
+		return p.APIBaseURL
+	case "organization":
+		return p.Organization
+	case "keyring_user":
+		return p.KeyringUser
+	case "proxy":
+		return p.Proxy
+	case "ca_file":
+		return p.CAFile
+	case "client_cert":
+		return p.ClientCert
+	case "client_key":
+		return p.ClientKey
+	case "insecure_skip_verify":
+		return strconv.FormatBool(p.InsecureSkipVerify)
+	case "oauth_url":
+		return p.OAuthURL
+	case "oauth_client_id":
+		return p.OAuthClientID
+	}
+	return ""
+}
+
+// defaultOrganization returns the organization of the current host profile,
+// falling back to the top-level 'organization' key.
+func defaultOrganization() string {
+	profile, err := currentHost()
+	if err != nil {
+		return viper.GetString("organization")
+	}
+	return profile.Organization
+}
+
+// hostsConfig returns the 'hosts' section as hostname -> settings.
+// It is stored as a list of maps with a 'host' field rather than a map keyed
+// by hostname, because viper splits keys on dots and would mangle hostnames.
+func hostsConfig() map[string]map[string]interface{} {
+	hosts := map[string]map[string]interface{}{}
+	raw, _ := viper.Get("hosts").([]interface{})
+	for _, entry := range raw {
+		settings, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := normalizeHostname(fmt.Sprint(settings["host"]))
+		if name == "" || settings["host"] == nil {
+			continue
+		}
+		hosts[name] = settings
+	}
+	return hosts
+}
+
+// hostSettings returns the settings map of a single profile, or nil.
+func hostSettings(hostname string) map[string]interface{} {
+	return hostsConfig()[hostname]
+}
+
+// setHostSetting sets (or, for a nil value, removes) a key of the profile for
+// hostname, creating the profile if needed. The caller is responsible for
+// writing the configuration file.
+func setHostSetting(hostname, key string, value interface{}) {
+	hosts := hostsConfig()
+	settings := hosts[hostname]
+	if settings == nil {
+		settings = map[string]interface{}{"host": hostname}
+	}
+	if value == nil {
+		delete(settings, key)
+	} else {
+		settings[key] = value
+	}
+	hosts[hostname] = settings
+
+	var list []interface{}
+	for _, name := range sortedHostnames(hosts) {
+		list = append(list, hosts[name])
+	}
+	viper.Set("hosts", list)
+}
+
+// configuredHostnames returns the names of all profiles in 'hosts', sorted.
+func configuredHostnames() []string {
+	return sortedHostnames(hostsConfig())
+}
+
+func sortedHostnames(hosts map[string]map[string]interface{}) []string {
+	var names []string
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func normalizeHostname(hostname string) string {
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	hostname = strings.TrimPrefix(hostname, "https://")
+	hostname = strings.TrimPrefix(hostname, "http://")
+	return strings.TrimSuffix(hostname, "/")
+}
@@ -0,0 +1,57 @@
+// cmd/hosts_test.go
+package cmd
+
+import (
+	"testing"
+
+	"cli-for-sourcecraft/internal/credstore"
+
+	"github.com/spf13/viper"
+)
+
+// TestTopLevelKeysOnlyForDefaultHost checks that the top-level keys and the
+// legacy 'token' of config.yaml are not applied to other hosts.
+func TestTopLevelKeysOnlyForDefaultHost(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	oldStore := credStore
+	credStore = credstore.EnvStore{}
+	t.Cleanup(func() { credStore = oldStore })
+	t.Setenv(tokenEnvVar, "")
+	t.Setenv(enterpriseTokenEnvVar, "")
+
+	viper.Set("token", "legacy-token")
+	viper.Set("api_base_url", "https://api.example")
+	viper.Set("keyring_user", "my-entry")
+	viper.Set("ca_file", "/certs/root.pem")
+	viper.Set("organization", "my-org")
+	viper.Set("hosts", []interface{}{
+		map[string]interface{}{"host": "corp.example", "api_base_url": "https://corp.example/api"},
+		map[string]interface{}{"host": "bare.example"},
+	})
+
+	def, err := resolveHost(defaultHostname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.APIBaseURL != "https://api.example" || def.KeyringUser != "my-entry" || def.CAFile != "/certs/root.pem" || def.Organization != "my-org" {
+		t.Errorf("%s profile = %+v, want the top-level keys", defaultHostname, def)
+	}
+	if token, source, _ := lookupToken(def); token != "legacy-token" || source != tokenSourceConfig {
+		t.Errorf("%s token = %q from %q, want the legacy config token", defaultHostname, token, source)
+	}
+
+	corp, err := resolveHost("corp.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corp.APIBaseURL != "https://corp.example/api" || corp.KeyringUser != keyringTokenUser+"@corp.example" || corp.CAFile != "" || corp.Organization != "" {
+		t.Errorf("corp.example profile = %+v, want nothing from the top-level keys", corp)
+	}
+	if token, source, err := lookupToken(corp); token != "" || source != "" || err != nil {
+		t.Errorf("corp.example token = %q from %q (error %v), want none", token, source, err)
+	}
+
+	if _, err := resolveHost("bare.example"); err == nil {
+		t.Error("bare.example without its own api_base_url: want an error instead of the top-level one")
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/api"
@@ -45,13 +46,17 @@ var issueCloseCmd = &cobra.Command{
 			StatusSlug: &statusClosed,
 		}
 
-		fmt.Printf("Closing a issue #%s в %s/%s...\n", issueSlug, orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Closing a issue #%s в %s/%s...\n", issueSlug, orgSlug, repoSlug)
 
-		updatedIssue, err := apiClient.UpdateIssue(orgSlug, repoSlug, issueSlug, body)
+		updatedIssue, err := apiClient.UpdateIssue(cmd.Context(), orgSlug, repoSlug, issueSlug, body)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(updatedIssue)
+		}
+
 		fmt.Println("\nThe issue was successfully closed!")
 		fmt.Printf("ID/Slug:    %s\n", cliutils.DerefString(updatedIssue.Slug))
 		if updatedIssue.Status != nil {
@@ -64,5 +69,6 @@ var issueCloseCmd = &cobra.Command{
 
 func init() {
 	issueCmd.AddCommand(issueCloseCmd)
+	supportsStructuredOutput(issueCloseCmd)
 	issueCloseCmd.Flags().StringVarP(&issueCloseRepoFlag, "repo", "R", "", "Specify repository <org>/<repo>")
 }
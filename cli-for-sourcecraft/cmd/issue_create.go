@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/api"
@@ -10,20 +11,23 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
 	issueCreateTitleFlag       string
 	issueCreateDescriptionFlag string
 	issueCreateRepoFlag        string
+	issueCreateLabelFlag       []string
 )
 
 var issueCreateCmd = &cobra.Command{
 	Use:   "create [flags]",
 	Short: "Create a new Issue",
-	Long:  `Creates a new issue in the SourceCraft repository.`,
-	Args:  cobra.NoArgs,
+	Long: `Creates a new issue in the SourceCraft repository.
+
+Labels are given by ID with --label; without it the 'issue.labels' key is used
+(e.g. from .sourcecraft/src.yaml of the repository).`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		var orgSlug, repoSlug string
@@ -37,17 +41,17 @@ var issueCreateCmd = &cobra.Command{
 			orgSlug = parts[0]
 			repoSlug = parts[1]
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not identify the repository from git remote and 'organization' is not set in the config. Use the --repo <org>/<repo>")
 				}
 				return fmt.Errorf("failed to identify repository slug from git remote. Use the --repo <org>/<repo>")
 			}
 		}
-		fmt.Printf("Creating an issue in the repository: %s/%s\n", orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Creating an issue in the repository: %s/%s\n", orgSlug, repoSlug)
 
 		title := issueCreateTitleFlag
 		if title == "" {
@@ -68,17 +72,27 @@ var issueCreateCmd = &cobra.Command{
 			}
 		}
 
+		labels := issueCreateLabelFlag
+		if len(labels) == 0 {
+			labels = repoSettingList("issue.labels")
+		}
+
 		apiBody := api.CreateIssueBody{
 			Title:       title,
 			Description: description,
+			LabelIDs:    labels,
 		}
 
-		fmt.Println("Creating Issue...")
-		createdIssue, err := apiClient.CreateIssue(orgSlug, repoSlug, apiBody)
+		fmt.Fprintln(os.Stderr, "Creating Issue...")
+		createdIssue, err := apiClient.CreateIssue(cmd.Context(), orgSlug, repoSlug, apiBody)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(createdIssue)
+		}
+
 		fmt.Println("\nThe issue has been successfully created!")
 		fmt.Printf("ID/Slug:    %s\n", cliutils.DerefString(createdIssue.Slug))
 		fmt.Printf("Title:  %s\n", cliutils.DerefString(createdIssue.Title))
@@ -95,8 +109,10 @@ var issueCreateCmd = &cobra.Command{
 
 func init() {
 	issueCmd.AddCommand(issueCreateCmd)
+	supportsStructuredOutput(issueCreateCmd)
 
 	issueCreateCmd.Flags().StringVarP(&issueCreateTitleFlag, "title", "t", "", "Issue Title")
 	issueCreateCmd.Flags().StringVarP(&issueCreateDescriptionFlag, "description", "d", "", "Issue Description")
+	issueCreateCmd.Flags().StringSliceVarP(&issueCreateLabelFlag, "label", "l", nil, "Label ID to add (repeatable or comma-separated; default: 'issue.labels' from config)")
 	issueCreateCmd.Flags().StringVarP(&issueCreateRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (Default: Current repository)")
 }
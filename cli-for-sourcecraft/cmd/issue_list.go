@@ -11,11 +11,12 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
-	issueListRepoFlag string
+	issueListRepoFlag  string
+	issueListLimitFlag int
+	issueListAllFlag   bool
 )
 
 var issueListCmd = &cobra.Command{
@@ -24,9 +25,12 @@ var issueListCmd = &cobra.Command{
 	Long:  `Shows the list of issues for the specified repository.`,
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := listOptions(issueListLimitFlag, issueListAllFlag)
+		if err != nil {
+			return err
+		}
 
 		var orgSlug, repoSlug string
-		var err error
 
 		if issueListRepoFlag != "" {
 			parts := strings.SplitN(issueListRepoFlag, "/", 2)
@@ -35,28 +39,32 @@ var issueListCmd = &cobra.Command{
 			}
 			orgSlug = parts[0]
 			repoSlug = parts[1]
-			fmt.Printf("Search for issues in the specified repository: %s/%s\n", orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Search for issues in the specified repository: %s/%s\n", orgSlug, repoSlug)
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not identify the repository from git remote and 'organization' is not set in the config. Use the --repo <org>/<repo>")
 				}
 				return fmt.Errorf("failed to identify the repository slug from git remote. Use the --repo <org>/<repo>")
 			}
-			fmt.Printf("Repository defined: %s/%s\n", orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Repository defined: %s/%s\n", orgSlug, repoSlug)
 		}
 
-		fmt.Printf("Request issues for %s/%s...\n", orgSlug, repoSlug)
-		issues, err := apiClient.ListRepositoryIssues(orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Request issues for %s/%s...\n", orgSlug, repoSlug)
+		issues, err := apiClient.ListRepositoryIssues(cmd.Context(), orgSlug, repoSlug, opts)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(issues)
+		}
+
 		if len(issues) == 0 {
-			fmt.Println("Issues not found.")
+			fmt.Fprintln(os.Stderr, "Issues not found.")
 			return nil
 		}
 
@@ -98,5 +106,7 @@ var issueListCmd = &cobra.Command{
 
 func init() {
 	issueCmd.AddCommand(issueListCmd)
+	supportsStructuredOutput(issueListCmd)
+	addListFlags(issueListCmd, &issueListLimitFlag, &issueListAllFlag)
 	issueListCmd.Flags().StringVarP(&issueListRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (Default: current repository)")
 }
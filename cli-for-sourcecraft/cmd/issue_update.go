@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/api"
@@ -71,18 +72,22 @@ Example: src issue update 12 --title "New title" --status "inProgress"`,
 		}
 
 		if !hasChanges {
-			fmt.Println("No flags are specified for the update. Completion.")
-			fmt.Println("Use the --title, --description, --status, --priority, --assignee.")
+			fmt.Fprintln(os.Stderr, "No flags are specified for the update. Completion.")
+			fmt.Fprintln(os.Stderr, "Use the --title, --description, --status, --priority, --assignee.")
 			return nil
 		}
 
-		fmt.Printf("Update issue #%s в %s/%s...\n", issueSlug, orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Update issue #%s в %s/%s...\n", issueSlug, orgSlug, repoSlug)
 
-		updatedIssue, err := apiClient.UpdateIssue(orgSlug, repoSlug, issueSlug, body)
+		updatedIssue, err := apiClient.UpdateIssue(cmd.Context(), orgSlug, repoSlug, issueSlug, body)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(updatedIssue)
+		}
+
 		fmt.Println("\nThe issue has been successfully updated!")
 		fmt.Printf("ID/Slug:    %s\n", cliutils.DerefString(updatedIssue.Slug))
 		fmt.Printf("Title:  %s\n", cliutils.DerefString(updatedIssue.Title))
@@ -99,6 +104,7 @@ Example: src issue update 12 --title "New title" --status "inProgress"`,
 
 func init() {
 	issueCmd.AddCommand(issueUpdateCmd)
+	supportsStructuredOutput(issueUpdateCmd)
 
 	issueUpdateCmd.Flags().StringVarP(&issueUpdateRepoFlag, "repo", "R", "", "Specify a repository <org>/<repo>")
 	issueUpdateCmd.Flags().StringVarP(&issueUpdateTitleFlag, "title", "t", "", "New issue title")
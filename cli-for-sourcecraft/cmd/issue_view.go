@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -34,19 +35,23 @@ var issueViewCmd = &cobra.Command{
 			orgSlug = parts[0]
 			repoSlug = parts[1]
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
 				return fmt.Errorf("the repository could not be determined. Use the --repo <org>/<repo>")
 			}
 		}
-		fmt.Printf("Issue request #%s в %s/%s\n", issueSlug, orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Issue request #%s в %s/%s\n", issueSlug, orgSlug, repoSlug)
 
-		issue, err := apiClient.GetIssue(orgSlug, repoSlug, issueSlug)
+		issue, err := apiClient.GetIssue(cmd.Context(), orgSlug, repoSlug, issueSlug)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(issue)
+		}
+
 		fmt.Printf("\n--- %s ---\n", cliutils.DerefString(issue.Title))
 		fmt.Printf("ID/Slug:    %s\n", cliutils.DerefString(issue.Slug))
 
@@ -85,5 +90,6 @@ var issueViewCmd = &cobra.Command{
 
 func init() {
 	issueCmd.AddCommand(issueViewCmd)
+	supportsStructuredOutput(issueViewCmd)
 	issueViewCmd.Flags().StringVarP(&issueViewRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (Default: current repository)")
 }
@@ -1,8 +1,260 @@
 // cmd/keyring.go
 package cmd
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/credstore"
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/spf13/viper"
+)
+
 // serviceName — это уникальный идентификатор для хранения в OS keyring.
 const keyringServiceName = "sourcecraft-cli"
 
-// keyringTokenUser — это "имя пользователя" или ключ, под которым хранится сам токен.
+// keyringTokenUser — ключ устаревшей единственной записи с токеном (до появления нескольких аккаунтов).
+// Такая запись автоматически переносится в запись аккаунта, см. migrateLegacyToken.
 const keyringTokenUser = "api_token"
+
+// Переменные окружения с токеном. Токен из переменной отдается только своему
+// хосту, чтобы токен sourcecraft.dev не уходил на другие серверы и наоборот.
+const (
+	tokenEnvVar           = "SOURCECRAFT_TOKEN"            // Только для defaultHostname
+	enterpriseTokenEnvVar = "SOURCECRAFT_ENTERPRISE_TOKEN" // Для всех остальных хостов
+)
+
+// Источники токена, в порядке приоритета. Для переменных окружения источник —
+// имя переменной (tokenEnvVar или enterpriseTokenEnvVar).
+const (
+	tokenSourceStore         = "credential store"
+	tokenSourceLegacyKeyring = "keyring (legacy)" // Единственная запись под keyring_user
+	tokenSourceConfig        = "config.yaml"      // Устаревшее хранение в открытом виде
+)
+
+// asUserFlag selects an account for a single command (--as).
+var asUserFlag string
+
+// credStore is opened on first use by credentialStore.
+var credStore credstore.Store
+
+// credentialStore returns the token store selected by 'credential_store' in
+// config.yaml: "keyring", "file" (encrypted credentials.enc in the config
+// directory, protected by $SRC_CREDENTIALS_PASSPHRASE or a machine key) or
+// "env" (nothing is stored, use SOURCECRAFT_TOKEN or SOURCECRAFT_ENTERPRISE_TOKEN,
+// see hostTokenEnvVar). By default the OS keyring
+// is used, or the encrypted file if no keyring is available.
+func credentialStore() (credstore.Store, error) {
+	if credStore != nil {
+		return credStore, nil
+	}
+	kind := strings.ToLower(strings.TrimSpace(viper.GetString("credential_store")))
+	store, err := credstore.Open(kind, keyringServiceName, filepath.Join(getConfigDir(), "credentials.enc"))
+	if err != nil {
+		return nil, err
+	}
+	credStore = store
+	return store, nil
+}
+
+// accountKeyringUser returns the keyring key of one account: "<host>:<user>".
+func accountKeyringUser(hostname, user string) string {
+	return hostname + ":" + user
+}
+
+// activeUser returns the account to use for profile: --as if given,
+// otherwise the active account of the profile ('user'), or "" if there is none.
+func activeUser(profile *hostProfile) (string, error) {
+	if asUserFlag == "" {
+		return profile.User, nil
+	}
+	if hasAccount(profile, asUserFlag) {
+		return asUserFlag, nil
+	}
+	if len(profile.Users) == 0 {
+		return "", fmt.Errorf("no accounts are logged in to %s. Run 'src auth login --hostname %s'", profile.Hostname, profile.Hostname)
+	}
+	return "", fmt.Errorf("account '%s' is not logged in to %s. Logged in accounts: %s", asUserFlag, profile.Hostname, strings.Join(profile.Users, ", "))
+}
+
+// hostTokenEnvVar returns the environment variable with the token for
+// hostname: SOURCECRAFT_TOKEN for sourcecraft.dev and
+// SOURCECRAFT_ENTERPRISE_TOKEN for every other host.
+func hostTokenEnvVar(hostname string) string {
+	if hostname == defaultHostname {
+		return tokenEnvVar
+	}
+	return enterpriseTokenEnvVar
+}
+
+// lookupToken finds the token for profile, in this order: the host's
+// environment variable (see hostTokenEnvVar; unless --as is given), the active
+// account in the OS keyring, the legacy single keyring entry and, for
+// sourcecraft.dev only, the legacy 'token' key in config.yaml.
+// It returns an empty token and source if none is set.
+func lookupToken(profile *hostProfile) (token, source string, err error) {
+	envVar := hostTokenEnvVar(profile.Hostname)
+	if token = os.Getenv(envVar); token != "" && asUserFlag == "" {
+		return token, envVar, nil
+	}
+
+	store, err := credentialStore()
+	if err != nil {
+		return "", "", err
+	}
+	user, err := activeUser(profile)
+	if err != nil {
+		return "", "", err
+	}
+	if user != "" {
+		token, err = store.Get(accountKeyringUser(profile.Hostname, user))
+		if errors.Is(err, credstore.ErrNotFound) {
+			return "", "", fmt.Errorf("the token of account '%s' on %s is missing from the %s. Run 'src auth login --hostname %s'", user, profile.Hostname, store.Description(), profile.Hostname)
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("error reading %s: %w", store.Description(), err)
+		}
+		return token, tokenSourceStore, nil
+	}
+
+	token, err = store.Get(profile.KeyringUser)
+	if err == nil && token != "" {
+		return token, tokenSourceLegacyKeyring, nil
+	}
+	if err != nil && !errors.Is(err, credstore.ErrNotFound) {
+		return "", "", fmt.Errorf("error reading %s: %w", store.Description(), err)
+	}
+
+	// Устаревший токен в config.yaml сохранялся только для sourcecraft.dev
+	if profile.Hostname != defaultHostname {
+		return "", "", nil
+	}
+	if token = viper.GetString("token"); token != "" {
+		return token, tokenSourceConfig, nil
+	}
+	return "", "", nil
+}
+
+// addAccount stores token for user on hostname and makes it the active
+// account. The caller is responsible for writing the configuration file.
+func addAccount(profile *hostProfile, user, token string) error {
+	store, err := credentialStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Set(accountKeyringUser(profile.Hostname, user), token); err != nil {
+		if errors.Is(err, credstore.ErrReadOnly) {
+			return fmt.Errorf("tokens are not stored with 'credential_store: env'. Set the %s environment variable instead", hostTokenEnvVar(profile.Hostname))
+		}
+		return fmt.Errorf("failed to save the token in %s: %w", store.Description(), err)
+	}
+	users := profile.Users
+	if !hasAccount(profile, user) {
+		users = append(users, user)
+	}
+	setHostSetting(profile.Hostname, "users", stringsToInterfaces(users))
+	setHostSetting(profile.Hostname, "user", user)
+	profile.Users = users
+	profile.User = user
+	return nil
+}
+
+// migrateLegacyToken moves the token of client from the legacy single keyring
+// entry or from config.yaml to a per-account entry. The account name comes
+// from GET /me; if that fails, the token is used as is and the migration is
+// retried by the next command.
+func migrateLegacyToken(ctx context.Context, client *api.Client, profile *hostProfile, source string) {
+	user, _, err := client.GetCurrentUser(ctx)
+	if err != nil || cliutils.DerefString(user.Slug) == "" {
+		return
+	}
+	slug := cliutils.DerefString(user.Slug)
+
+	fmt.Fprintf(os.Stderr, "Migrating the token from %s to account '%s'...\n", source, slug)
+	if err := addAccount(profile, slug, client.Token); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if source == tokenSourceLegacyKeyring {
+		if err := deleteSecret(profile.KeyringUser); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to remove the legacy keyring entry: %v\n", err)
+		}
+	}
+	if err := saveConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to save the configuration after migration: %v\n", err)
+		return
+	}
+	if source == tokenSourceConfig {
+		if _, err := unsetConfigKeys("token"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to remove the token from the configuration file: %v\n", err)
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, "The migration is complete.")
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// removeAccount deletes the token of user on the profile's host. If user was
+// the active account, the first remaining one becomes active. The caller is
+// responsible for writing the configuration file.
+func removeAccount(profile *hostProfile, user string) error {
+	if err := deleteSecret(accountKeyringUser(profile.Hostname, user)); err != nil {
+		return fmt.Errorf("failed to remove the token: %w", err)
+	}
+	if err := deleteSecret(refreshKeyringUser(profile.Hostname, user)); err != nil {
+		return fmt.Errorf("failed to remove the refresh token: %w", err)
+	}
+
+	var users []string
+	for _, u := range profile.Users {
+		if u != user {
+			users = append(users, u)
+		}
+	}
+	if profile.User == user {
+		profile.User = ""
+		if len(users) > 0 {
+			profile.User = users[0]
+		}
+	}
+	profile.Users = users
+
+	if len(users) == 0 {
+		setHostSetting(profile.Hostname, "users", nil)
+		setHostSetting(profile.Hostname, "user", nil)
+	} else {
+		setHostSetting(profile.Hostname, "users", stringsToInterfaces(users))
+		setHostSetting(profile.Hostname, "user", profile.User)
+	}
+	return nil
+}
+
+// hasAccount reports whether user is logged in to the profile's host.
+func hasAccount(profile *hostProfile, user string) bool {
+	return containsString(profile.Users, user)
+}
+
+// deleteSecret removes key from the credential store; a missing key is not an error.
+func deleteSecret(key string) error {
+	store, err := credentialStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(key); err != nil && !errors.Is(err, credstore.ErrNotFound) {
+		return fmt.Errorf("%s: %w", store.Description(), err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// cmd/list_flags.go
+package cmd
+
+import (
+	"fmt"
+
+	"cli-for-sourcecraft/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+// addListFlags регистрирует --limit и --all для команд 'list'.
+func addListFlags(cmd *cobra.Command, limit *int, all *bool) {
+	cmd.Flags().IntVarP(limit, "limit", "L", api.DefaultListLimit, "Maximum number of items to fetch")
+	cmd.Flags().BoolVar(all, "all", false, "Fetch all items, following pagination to the last page")
+	cmd.MarkFlagsMutuallyExclusive("limit", "all")
+}
+
+// listOptions validates the --limit/--all values and converts them to api.ListOptions.
+func listOptions(limit int, all bool) (api.ListOptions, error) {
+	if !all && limit <= 0 {
+		return api.ListOptions{}, fmt.Errorf("invalid value for --limit: %d. Must be greater than 0", limit)
+	}
+	return api.ListOptions{Limit: limit, All: all}, nil
+}
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -11,7 +12,6 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -48,17 +48,17 @@ Example: src milestone create --name "Release 1.0" --deadline "2025-12-31"`,
 			orgSlug = parts[0]
 			repoSlug = parts[1]
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not identify the repository from git remote and 'organization' is not set in the config. Use the --repo <org>/<repo>")
 				}
 				return fmt.Errorf("failed to identify repository slug from git remote. Use the --repo <org>/<repo>")
 			}
 		}
-		fmt.Printf("Create a milestone in a repository: %s/%s\n", orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Create a milestone in a repository: %s/%s\n", orgSlug, repoSlug)
 
 		apiBody := api.CreateMilestoneBody{
 			Name:        msCreateNameFlag,
@@ -79,12 +79,16 @@ Example: src milestone create --name "Release 1.0" --deadline "2025-12-31"`,
 			}
 		}
 
-		fmt.Println("Creating milestone...")
-		createdMS, err := apiClient.CreateMilestone(orgSlug, repoSlug, apiBody)
+		fmt.Fprintln(os.Stderr, "Creating milestone...")
+		createdMS, err := apiClient.CreateMilestone(cmd.Context(), orgSlug, repoSlug, apiBody)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(createdMS)
+		}
+
 		fmt.Println("\nMilestone successfully created!")
 		fmt.Printf("ID/Slug:    %s\n", cliutils.DerefString(createdMS.Slug))
 		fmt.Printf("Title:   %s\n", cliutils.DerefString(createdMS.Name))
@@ -107,6 +111,7 @@ func parseDateToRFC3339(dateStr string) (string, error) {
 
 func init() {
 	milestoneCmd.AddCommand(milestoneCreateCmd)
+	supportsStructuredOutput(milestoneCreateCmd)
 
 	milestoneCreateCmd.Flags().StringVarP(&msCreateRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (Default: current repository)")
 	milestoneCreateCmd.Flags().StringVarP(&msCreateNameFlag, "name", "n", "", "Milestone Name (Required)")
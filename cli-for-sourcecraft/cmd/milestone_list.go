@@ -12,11 +12,12 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
-	milestoneListRepoFlag string
+	milestoneListRepoFlag  string
+	milestoneListLimitFlag int
+	milestoneListAllFlag   bool
 )
 
 var milestoneListCmd = &cobra.Command{
@@ -24,9 +25,12 @@ var milestoneListCmd = &cobra.Command{
 	Short: "View the list of milestones in the repository",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := listOptions(milestoneListLimitFlag, milestoneListAllFlag)
+		if err != nil {
+			return err
+		}
 
 		var orgSlug, repoSlug string
-		var err error
 
 		if milestoneListRepoFlag != "" {
 			parts := strings.SplitN(milestoneListRepoFlag, "/", 2)
@@ -36,25 +40,29 @@ var milestoneListCmd = &cobra.Command{
 			orgSlug = parts[0]
 			repoSlug = parts[1]
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("couldn't identify repository from git remote and 'organization' is not set in config. Use the --repo <org>/<repo>")
 				}
 				return fmt.Errorf("failed to identify repository slug from git remote. Use the --repo <org>/<repo>")
 			}
 		}
-		fmt.Printf("Querying milestones for a repository: %s/%s\n", orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Querying milestones for a repository: %s/%s\n", orgSlug, repoSlug)
 
-		milestones, err := apiClient.ListMilestonesForRepository(orgSlug, repoSlug)
+		milestones, err := apiClient.ListMilestonesForRepository(cmd.Context(), orgSlug, repoSlug, opts)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(milestones)
+		}
+
 		if len(milestones) == 0 {
-			fmt.Println("No milestones found.")
+			fmt.Fprintln(os.Stderr, "No milestones found.")
 			return nil
 		}
 
@@ -103,5 +111,7 @@ func formatMilestoneDate(ts *string) string {
 
 func init() {
 	milestoneCmd.AddCommand(milestoneListCmd)
+	supportsStructuredOutput(milestoneListCmd)
+	addListFlags(milestoneListCmd, &milestoneListLimitFlag, &milestoneListAllFlag)
 	milestoneListCmd.Flags().StringVarP(&milestoneListRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (Default: current repository)")
 }
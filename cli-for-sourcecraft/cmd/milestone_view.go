@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -33,19 +34,23 @@ var milestoneViewCmd = &cobra.Command{
 			orgSlug = parts[0]
 			repoSlug = parts[1]
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
 				return fmt.Errorf("the repository could not be determined. Use the --repo <org>/<repo>")
 			}
 		}
-		fmt.Printf("Milestone Request '%s' в %s/%s\n", milestoneSlug, orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Milestone Request '%s' в %s/%s\n", milestoneSlug, orgSlug, repoSlug)
 
-		ms, err := apiClient.GetMilestone(orgSlug, repoSlug, milestoneSlug)
+		ms, err := apiClient.GetMilestone(cmd.Context(), orgSlug, repoSlug, milestoneSlug)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(ms)
+		}
+
 		fmt.Printf("\n--- %s ---\n", cliutils.DerefString(ms.Name))
 		fmt.Printf("ID/Slug:     %s\n", cliutils.DerefString(ms.Slug))
 		fmt.Printf("Status:      %s\n", cliutils.DerefString(ms.Status))
@@ -81,5 +86,6 @@ var milestoneViewCmd = &cobra.Command{
 
 func init() {
 	milestoneCmd.AddCommand(milestoneViewCmd)
+	supportsStructuredOutput(milestoneViewCmd)
 	milestoneViewCmd.Flags().StringVarP(&milestoneViewRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (Default: current repository)")
 }
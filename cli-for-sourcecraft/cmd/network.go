@@ -0,0 +1,122 @@
+// cmd/network.go
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+// setupTransport replaces the client's transport with one that uses the
+// proxy, CA bundle and client certificate of profile.
+func setupTransport(client *api.Client, profile *hostProfile) error {
+	if profile.InsecureSkipVerify {
+		fmt.Fprintf(os.Stderr, "WARNING: TLS certificate verification is DISABLED for host '%s' (insecure_skip_verify).\n", profile.Hostname)
+		fmt.Fprintln(os.Stderr, "WARNING: Anyone on the network can intercept your token. Use this only in lab environments.")
+	}
+	tr, err := profileTransport(profile)
+	if err != nil {
+		return err
+	}
+	client.HTTPClient.Transport = tr
+	return nil
+}
+
+// profileTransport builds an http.Transport with the network settings of profile.
+func profileTransport(profile *hostProfile) (*http.Transport, error) {
+	tr, err := api.NewTransport(api.TransportConfig{
+		Proxy:              profile.Proxy,
+		CAFile:             profile.CAFile,
+		ClientCert:         profile.ClientCert,
+		ClientKey:          profile.ClientKey,
+		InsecureSkipVerify: profile.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("host '%s': %w", profile.Hostname, err)
+	}
+	return tr, nil
+}
+
+// gitEnv returns the environment for git subprocesses that talk to the
+// current host: the same CA bundle, client certificate and proxy as the API
+// client. Proxy variables the user has already set are left alone.
+//
+// The TLS settings are passed as http.<url>.* keys (see gitHostConfig) that
+// apply to the host and its subdomains only: git's sslCAInfo replaces its
+// default CA list instead of adding to it, so a global GIT_SSL_CAINFO with
+// just the corporate root would break every other remote.
+func gitEnv() []string {
+	env := os.Environ()
+	profile, err := currentHost()
+	if err != nil {
+		return env
+	}
+
+	set := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if _, ok := os.LookupEnv(key); ok {
+			return
+		}
+		env = append(env, key+"="+value)
+	}
+	env = append(env, gitHostConfig(profile)...)
+	if p := strings.TrimSpace(profile.Proxy); p != "" && !strings.EqualFold(p, "direct") {
+		set("HTTPS_PROXY", p)
+		set("HTTP_PROXY", p)
+	}
+	return env
+}
+
+// gitHostConfig returns GIT_CONFIG_COUNT, GIT_CONFIG_KEY_<n> and
+// GIT_CONFIG_VALUE_<n> that set sslCAInfo, sslCert, sslKey and sslVerify for
+// https://<host>/ and https://*.<host>/ (e.g. git.<host>). Entries the user
+// passes the same way are kept; GIT_SSL_* variables still win over them.
+func gitHostConfig(profile *hostProfile) []string {
+	var keys, values []string
+	for _, prefix := range []string{"http.https://" + profile.Hostname + "/.", "http.https://*." + profile.Hostname + "/."} {
+		add := func(key, value string) {
+			if value != "" {
+				keys = append(keys, prefix+key)
+				values = append(values, value)
+			}
+		}
+		add("sslCAInfo", profile.CAFile)
+		add("sslCert", profile.ClientCert)
+		add("sslKey", profile.ClientKey)
+		if profile.InsecureSkipVerify {
+			add("sslVerify", "false")
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	count := 0
+	if n, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT")); err == nil && n > 0 {
+		count = n
+	}
+	var env []string
+	for i := range keys {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", count+i, keys[i]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count+i, values[i]))
+	}
+	return append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+len(keys)))
+}
+
+// expandHome expands a leading "~/" in a configured file path.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
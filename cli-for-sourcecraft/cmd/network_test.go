@@ -0,0 +1,70 @@
+// cmd/network_test.go
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// TestGitEnvScopesTLSToHost asks git itself which sslCAInfo applies to a URL
+// in the environment built by gitEnv.
+func TestGitEnvScopesTLSToHost(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Cleanup(viper.Reset)
+	viper.Set("hosts", []interface{}{map[string]interface{}{
+		"host":         "src.corp.example",
+		"api_base_url": "https://src.corp.example/api",
+		"ca_file":      "/certs/corp-root.pem",
+		"client_cert":  "/certs/me.pem",
+	}})
+	oldHostname := hostnameFlag
+	hostnameFlag = "src.corp.example"
+	t.Cleanup(func() { hostnameFlag = oldHostname })
+	// Уже переданный пользователем ключ сохраняется
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "user.name")
+	t.Setenv("GIT_CONFIG_VALUE_0", "Tester")
+
+	env := gitEnv()
+	for _, kv := range env[len(os.Environ()):] {
+		if strings.HasPrefix(kv, "GIT_SSL_") {
+			t.Errorf("gitEnv() sets %s globally", kv)
+		}
+	}
+
+	gitConfig := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"config"}, args...)...)
+		cmd.Env = env
+		out, _ := cmd.Output()
+		return strings.TrimSpace(string(out))
+	}
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://src.corp.example/org/repo.git", "/certs/corp-root.pem"},
+		{"https://git.src.corp.example/org/repo.git", "/certs/corp-root.pem"},
+		{"https://github.com/org/repo.git", ""},
+		{"https://src.corp.example.evil.test/org/repo.git", ""},
+	}
+	for _, tt := range tests {
+		if got := gitConfig("--get-urlmatch", "http.sslCAInfo", tt.url); got != tt.want {
+			t.Errorf("sslCAInfo for %s = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+	if got := gitConfig("--get-urlmatch", "http.sslCert", "https://src.corp.example/"); got != "/certs/me.pem" {
+		t.Errorf("sslCert = %q, want /certs/me.pem", got)
+	}
+	if got := gitConfig("--get-urlmatch", "http.sslVerify", "https://src.corp.example/"); got != "" {
+		t.Errorf("sslVerify = %q, want it unset", got)
+	}
+	if got := gitConfig("--get", "user.name"); got != "Tester" {
+		t.Errorf("user.name = %q, want the user's GIT_CONFIG_KEY_0 kept", got)
+	}
+}
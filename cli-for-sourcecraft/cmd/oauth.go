@@ -0,0 +1,106 @@
+// cmd/oauth.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/oauth"
+)
+
+// defaultOAuthClientID — OAuth-клиент src, если в профиле не задан 'oauth_client_id'.
+const defaultOAuthClientID = "sourcecraft-cli"
+
+// oauthConfig returns the authorization server of profile: the device and
+// token endpoints under 'oauth_url' (default https://<host>). Its requests
+// are traced like API calls (--verbose, --trace-file).
+func oauthConfig(profile *hostProfile) (*oauth.Config, error) {
+	profileTr, err := profileTransport(profile)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := tracingTransport(profileTr)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth.Config{
+		ClientID:      profile.OAuthClientID,
+		DeviceAuthURL: profile.OAuthURL + "/oauth/device/code",
+		TokenURL:      profile.OAuthURL + "/oauth/token",
+		HTTPClient:    &http.Client{Transport: tr},
+	}, nil
+}
+
+// refreshKeyringUser returns the credential store key of an account's OAuth refresh token.
+func refreshKeyringUser(hostname, user string) string {
+	return accountKeyringUser(hostname, user) + ":refresh"
+}
+
+// setupTokenRefresh lets client renew an expired access token of the active
+// account if it was obtained with 'auth login --web' and has a refresh token.
+// The new tokens are saved to the credential store.
+func setupTokenRefresh(client *api.Client, profile *hostProfile) {
+	user, err := activeUser(profile)
+	if err != nil || user == "" {
+		return
+	}
+	store, err := credentialStore()
+	if err != nil {
+		return
+	}
+	refreshToken, err := store.Get(refreshKeyringUser(profile.Hostname, user))
+	if err != nil || refreshToken == "" {
+		return
+	}
+
+	client.Refresh = func(ctx context.Context) (string, error) {
+		cfg, err := oauthConfig(profile)
+		if err != nil {
+			return "", err
+		}
+		tok, err := cfg.Refresh(ctx, refreshToken)
+		if err != nil {
+			return "", fmt.Errorf("%w. Run 'src auth login --web' again", err)
+		}
+		if err := store.Set(accountKeyringUser(profile.Hostname, user), tok.AccessToken); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save the refreshed token: %v\n", err)
+		}
+		if tok.RefreshToken != refreshToken {
+			if err := store.Set(refreshKeyringUser(profile.Hostname, user), tok.RefreshToken); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to save the new refresh token: %v\n", err)
+			}
+			refreshToken = tok.RefreshToken
+		}
+		return tok.AccessToken, nil
+	}
+}
+
+// deviceFlowLogin runs the OAuth device authorization flow for profile and
+// returns the access and refresh tokens.
+func deviceFlowLogin(ctx context.Context, profile *hostProfile) (*oauth.Token, error) {
+	cfg, err := oauthConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := cfg.RequestDeviceCode(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start browser login: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "First copy your one-time code: %s\n", dc.UserCode)
+	if dc.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "Then open %s in your browser.\n", dc.VerificationURIComplete)
+	} else {
+		fmt.Fprintf(os.Stderr, "Then open %s in your browser and enter the code.\n", dc.VerificationURI)
+	}
+	fmt.Fprintln(os.Stderr, "Waiting for authorization...")
+
+	tok, err := cfg.PollToken(ctx, dc)
+	if err != nil {
+		return nil, fmt.Errorf("browser login failed: %w", err)
+	}
+	return tok, nil
+}
@@ -0,0 +1,69 @@
+// cmd/output.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"cli-for-sourcecraft/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+// outputOpts holds the global --json, --jq and --template flags.
+var outputOpts output.Options
+
+// structuredOutputAnnotation marks commands whose result can be printed with --json.
+const structuredOutputAnnotation = "structured-output"
+
+// supportsStructuredOutput marks cmd as accepting --json, --jq and --template.
+func supportsStructuredOutput(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[structuredOutputAnnotation] = "true"
+}
+
+// checkOutputFlags rejects --json/--jq/--template for commands that do not
+// support them and validates the jq expression and template up front.
+func checkOutputFlags(cmd *cobra.Command) error {
+	if !outputOpts.Enabled() {
+		return nil
+	}
+	if cmd.Annotations[structuredOutputAnnotation] != "true" {
+		return fmt.Errorf("'%s' does not support --json, --jq or --template", cmd.CommandPath())
+	}
+	return outputOpts.Validate()
+}
+
+// jsonFieldList matches a comma-separated field list such as "slug,title".
+var jsonFieldList = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*(,[A-Za-z_][A-Za-z0-9_.]*)+$`)
+
+// checkJSONFieldsArg rejects "--json slug,title": --json has an optional value,
+// so its fields must follow '='. Otherwise cobra takes the list for a positional
+// argument and fails with a confusing "accepts N arg(s)" error or uses it as one.
+func checkJSONFieldsArg(args []string) error {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--json" && i+1 < len(args) && jsonFieldList.MatchString(args[i+1]) {
+			return fmt.Errorf("--json takes the field list after '=': use --json=%s", args[i+1])
+		}
+	}
+	return nil
+}
+
+// printStructured prints v according to --json, --jq or --template.
+func printStructured(v interface{}) error {
+	return outputOpts.Write(os.Stdout, v)
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&outputOpts.JSON, "json", "", "Output JSON. Optionally limit to comma-separated fields with --json=slug,title (the '=' is required, '--json slug,title' is not supported)")
+	flags.Lookup("json").NoOptDefVal = output.AllFields
+	flags.StringVar(&outputOpts.JQ, "jq", "", "Filter JSON output with a jq expression (implies --json)")
+	flags.StringVar(&outputOpts.Template, "template", "", "Format JSON output with a Go text/template (implies --json)")
+}
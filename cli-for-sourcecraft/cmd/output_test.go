@@ -0,0 +1,32 @@
+// cmd/output_test.go
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckJSONFieldsArg(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"pr", "list", "--json", "slug,title"}, true},
+		{[]string{"--json", "slug,author.login", "pr", "list"}, true},
+		{[]string{"pr", "list", "--json=slug,title"}, false},
+		{[]string{"pr", "list", "--json"}, false},
+		{[]string{"pr", "view", "--json", "42"}, false},
+		{[]string{"repo", "view", "--json", "org/repo"}, false},
+		{[]string{"api", "--", "--json", "a,b"}, false},
+		{[]string{"pr", "create", "--title", "a,b"}, false},
+	}
+	for _, tt := range tests {
+		err := checkJSONFieldsArg(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkJSONFieldsArg(%q) error = %v, want error %v", tt.args, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "--json=") {
+			t.Errorf("checkJSONFieldsArg(%q) error = %v, want a hint with --json=", tt.args, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// cmd/pager.go
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdoutIsTerminal reports whether the output goes to a terminal.
+func stdoutIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// startPager pipes the output through $PAGER (default: less) when stdout is a
+// terminal. PAGER=cat or an empty PAGER turns it off. It returns the writer to
+// print to and a function that waits for the pager to exit.
+func startPager() (io.Writer, func()) {
+	noPager := func() {}
+	if !stdoutIsTerminal() {
+		return os.Stdout, noPager
+	}
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = "less"
+	}
+	fields := strings.Fields(pager)
+	if len(fields) == 0 || fields[0] == "cat" {
+		return os.Stdout, noPager
+	}
+
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		// Выйти, если всё помещается на экран, и пропускать цвета
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, noPager
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not start pager '%s': %v\n", pager, err)
+		return os.Stdout, noPager
+	}
+	return in, func() {
+		in.Close()
+		cmd.Wait()
+	}
+}
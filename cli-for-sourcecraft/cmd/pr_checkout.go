@@ -0,0 +1,205 @@
+// cmd/pr_checkout.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/git"
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	prCheckoutRepoFlag   string
+	prCheckoutBranchFlag string
+	prCheckoutForceFlag  bool
+)
+
+var prCheckoutCmd = &cobra.Command{
+	Use:   "checkout <pr_id_or_slug>",
+	Short: "Check out the source branch of a pull request locally",
+	Long: `Fetches the source branch of a pull request and switches to a local branch
+that tracks it. An existing local branch is fast-forwarded.
+
+For a pull request from a fork, a remote named after the fork owner is added
+(unless a remote for the fork already exists), using SSH or HTTPS like 'origin'.
+
+The command refuses to run with uncommitted changes. --force skips this check
+(git still keeps changes that do not conflict) and resets an existing local
+branch to the pull request, e.g. after its author force-pushed.
+
+Example: src pr checkout 12`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prSlug := args[0]
+		var orgSlug, repoSlug string
+		var err error
+
+		if prCheckoutRepoFlag != "" {
+			parts := strings.SplitN(prCheckoutRepoFlag, "/", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid format for --repo flag: '%s'. Expected format: <org_slug>/<repo_slug>", prCheckoutRepoFlag)
+			}
+			orgSlug = parts[0]
+			repoSlug = parts[1]
+		} else {
+			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
+			if err != nil {
+				return fmt.Errorf("could not detect repository from git remote. Use --repo <org>/<repo> flag or run from within a repository")
+			}
+		}
+
+		// 1. Рабочая копия должна быть чистой
+		if !prCheckoutForceFlag {
+			dirty, err := git.HasUncommittedChanges()
+			if err != nil {
+				return err
+			}
+			if dirty {
+				return errors.New("you have uncommitted changes. Commit or stash them, or use --force")
+			}
+		}
+
+		// 2. Откуда брать ветку
+		pr, err := apiClient.GetPullRequest(cmd.Context(), orgSlug, repoSlug, prSlug)
+		if err != nil {
+			return err
+		}
+		sourceBranch := cliutils.DerefString(pr.SourceBranch)
+		if sourceBranch == "" {
+			return fmt.Errorf("the API returned no source branch for PR #%s", prSlug)
+		}
+		remote, err := prSourceRemote(cmd.Context(), pr, orgSlug, repoSlug)
+		if err != nil {
+			return err
+		}
+
+		// 3. Fetch в refs/remotes/<remote>/<branch>, независимо от refspec remote
+		remoteRef := remote + "/" + sourceBranch
+		fmt.Fprintf(os.Stderr, "Fetching '%s' from '%s'...\n", sourceBranch, remote)
+		if err := runGitCommand("fetch", remote, fmt.Sprintf("+refs/heads/%s:refs/remotes/%s", sourceBranch, remoteRef)); err != nil {
+			return fmt.Errorf("failed to fetch branch '%s' from '%s': %w", sourceBranch, remote, err)
+		}
+
+		// 4. Локальная ветка
+		localBranch := prCheckoutBranchFlag
+		if localBranch == "" {
+			localBranch = sourceBranch
+		}
+		current, _ := git.GetCurrentBranchName()
+		exists := git.BranchExists(localBranch)
+		switch {
+		case !exists:
+			if err := runGitCommand("checkout", "-b", localBranch, "--track", remoteRef); err != nil {
+				return fmt.Errorf("failed to create branch '%s': %w", localBranch, err)
+			}
+		case prCheckoutForceFlag:
+			if err := runGitCommand("checkout", "-B", localBranch, remoteRef); err != nil {
+				return fmt.Errorf("failed to reset branch '%s' to '%s': %w", localBranch, remoteRef, err)
+			}
+		default:
+			// Проверяем до переключения, чтобы не оставить пользователя на разошедшейся ветке
+			if exec.Command("git", "merge-base", "--is-ancestor", localBranch, remoteRef).Run() != nil {
+				return fmt.Errorf("local branch '%s' has diverged from '%s' (local commits, or the author force-pushed). Use --force to reset it to the pull request", localBranch, remoteRef)
+			}
+			if current != localBranch {
+				if err := runGitCommand("checkout", localBranch); err != nil {
+					return fmt.Errorf("failed to switch to branch '%s': %w", localBranch, err)
+				}
+			}
+			if err := runGitCommand("merge", "--ff-only", remoteRef); err != nil {
+				return fmt.Errorf("failed to fast-forward '%s' to '%s': %w", localBranch, remoteRef, err)
+			}
+		}
+		if exists {
+			// Новая ветка уже создана с --track
+			if err := runGitCommand("branch", "--set-upstream-to="+remoteRef, localBranch); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not set '%s' as the upstream of '%s': %v\n", remoteRef, localBranch, err)
+			}
+		}
+
+		fmt.Printf("Switched to branch '%s' (PR #%s: %s)\n", localBranch, prSlug, cliutils.DerefString(pr.Title))
+		return nil
+	},
+}
+
+// prSourceRemote returns the git remote of the repository the PR branch lives
+// in. For a PR from a fork a remote is added if there is none yet.
+func prSourceRemote(ctx context.Context, pr *api.PullRequest, orgSlug, repoSlug string) (string, error) {
+	sourceOrg, sourceRepo := orgSlug, repoSlug
+	src := pr.SourceRepository
+	if src != nil && src.Owner != nil && cliutils.DerefString(src.Owner.Slug) != "" && cliutils.DerefString(src.Slug) != "" {
+		sourceOrg, sourceRepo = cliutils.DerefString(src.Owner.Slug), cliutils.DerefString(src.Slug)
+	}
+
+	remote, err := git.FindRemoteForRepo(sourceOrg, sourceRepo)
+	if err != nil {
+		return "", err
+	}
+	if remote != "" {
+		return remote, nil
+	}
+	if strings.EqualFold(sourceOrg, orgSlug) && strings.EqualFold(sourceRepo, repoSlug) {
+		return "", fmt.Errorf("no git remote points to %s/%s. Run the command in a clone of the repository", orgSlug, repoSlug)
+	}
+
+	// Форк: добавляем remote по имени владельца, с тем же протоколом, что у origin
+	originURL, _ := git.GetRemoteURL("origin")
+	useHTTPS := strings.HasPrefix(originURL, "https://")
+	var cloneURL *api.CloneURL
+	if src != nil {
+		cloneURL = src.CloneURL
+	}
+	url := pickCloneURL(cloneURL, useHTTPS)
+	if url == "" {
+		repoInfo, err := apiClient.GetRepository(ctx, sourceOrg, sourceRepo)
+		if err != nil {
+			return "", fmt.Errorf("failed to get clone URL of the fork %s/%s: %w", sourceOrg, sourceRepo, err)
+		}
+		url = pickCloneURL(repoInfo.CloneURL, useHTTPS)
+	}
+	if url == "" {
+		return "", fmt.Errorf("no clone URL available for the fork %s/%s", sourceOrg, sourceRepo)
+	}
+
+	remotes, err := git.GetRemotes()
+	if err != nil {
+		return "", err
+	}
+	name := sourceOrg
+	if _, taken := remotes[name]; taken {
+		name = sourceOrg + "-" + sourceRepo
+	}
+	fmt.Fprintf(os.Stderr, "Adding remote '%s' for the fork %s/%s...\n", name, sourceOrg, sourceRepo)
+	if err := runGitCommand("remote", "add", name, url); err != nil {
+		return "", fmt.Errorf("failed to add remote '%s': %w", name, err)
+	}
+	return name, nil
+}
+
+// pickCloneURL returns the HTTPS or SSH clone URL, falling back to the other one.
+func pickCloneURL(cloneURL *api.CloneURL, useHTTPS bool) string {
+	if cloneURL == nil {
+		return ""
+	}
+	https, ssh := cliutils.DerefString(cloneURL.HTTPS), cliutils.DerefString(cloneURL.SSH)
+	if useHTTPS && https != "" || ssh == "" {
+		return https
+	}
+	return ssh
+}
+
+func init() {
+	prCmd.AddCommand(prCheckoutCmd)
+
+	prCheckoutCmd.Flags().StringVarP(&prCheckoutRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (default: current directory)")
+	prCheckoutCmd.Flags().StringVarP(&prCheckoutBranchFlag, "branch", "b", "", "Name of the local branch (default: the source branch of the pull request)")
+	prCheckoutCmd.Flags().BoolVarP(&prCheckoutForceFlag, "force", "f", false, "Check out despite uncommitted changes and reset an existing local branch")
+}
@@ -12,7 +12,6 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -31,6 +30,11 @@ var prCreateCmd = &cobra.Command{
 	Long: `Creates a Pull Request on SourceCraft.
 
 By default, it uses the current branch as the source (--head) and the repository's default branch (main/master) as the target (--base).
+The target branch and the reviewers can be preset with the 'pr.base' and 'pr.reviewers' keys,
+e.g. in .sourcecraft/src.yaml of the repository (see 'src config set --help').
+Reviewers are given by login (slug) or user ID. Without --reviewer, the owners of the
+changed files from a CODEOWNERS file (.sourcecraft/CODEOWNERS, CODEOWNERS or
+docs/CODEOWNERS) are requested in addition to 'pr.reviewers'.
 It will prompt for a Title and Description if they are not provided via flags.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,52 +50,57 @@ It will prompt for a Title and Description if they are not provided via flags.`,
 			}
 			orgSlug = parts[0]
 			repoSlug = parts[1]
-			fmt.Printf("Target repository: %s/%s\n", orgSlug, repoSlug)
-			fmt.Println("Get repository details...")
-			repoInfo, err = apiClient.GetRepository(orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Target repository: %s/%s\n", orgSlug, repoSlug)
+			fmt.Fprintln(os.Stderr, "Get repository details...")
+			repoInfo, err = apiClient.GetRepository(cmd.Context(), orgSlug, repoSlug)
 			if err != nil {
-				fmt.Printf("Note: Repository details could not be retrieved: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Note: Repository details could not be retrieved: %v\n", err)
 			}
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not identify repository from git remote and 'organization' not specified in the config. Use the --repo <org>/<repo>")
 				}
 				return fmt.Errorf("failed to identify repository slug from git remote. Use the --repo <org>/<repo>")
 			}
-			fmt.Printf("Repository defined: %s/%s\n", orgSlug, repoSlug)
-			fmt.Println("Get repository details...")
-			repoInfo, err = apiClient.GetRepository(orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Repository defined: %s/%s\n", orgSlug, repoSlug)
+			fmt.Fprintln(os.Stderr, "Get repository details...")
+			repoInfo, err = apiClient.GetRepository(cmd.Context(), orgSlug, repoSlug)
 			if err != nil {
-				fmt.Printf("Warning: Repository details could not be retrieved: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Warning: Repository details could not be retrieved: %v\n", err)
 			}
 		}
 
 		headBranch := prCreateHeadBranchFlag
 		if headBranch == "" {
-			fmt.Println("Determining the current branch...")
+			fmt.Fprintln(os.Stderr, "Determining the current branch...")
 			headBranch, err = git.GetCurrentBranchName()
 			if err != nil {
 				return fmt.Errorf("could not get the current branch: %w. Use the --head", err)
 			}
-			fmt.Printf("Using the current branch as the source branch: %s\n", headBranch)
+			fmt.Fprintf(os.Stderr, "Using the current branch as the source branch: %s\n", headBranch)
 		} else {
-			fmt.Printf("Use the specified source branch: %s\n", headBranch)
+			fmt.Fprintf(os.Stderr, "Use the specified source branch: %s\n", headBranch)
 		}
 
 		baseBranch := prCreateBaseBranchFlag
-		if baseBranch == "" {
-			fmt.Println("Definition of the default branch...")
+		configuredBase := repoSettingString("pr.base")
+		switch {
+		case baseBranch != "":
+			fmt.Fprintf(os.Stderr, "Use the specified target branch: %s\n", baseBranch)
+		case configuredBase != "":
+			baseBranch = configuredBase
+			fmt.Fprintf(os.Stderr, "Use the target branch from 'pr.base': %s\n", baseBranch)
+		default:
+			fmt.Fprintln(os.Stderr, "Definition of the default branch...")
 			baseBranch, err = git.GetDefaultBranchName(repoInfo, "origin")
 			if err != nil {
 				return fmt.Errorf("the default branch could not be determined: %w. Use the --base", err)
 			}
-			fmt.Printf("Use the default branch of the repository as the target branch: %s\n", baseBranch)
-		} else {
-			fmt.Printf("Use the specified target branch: %s\n", baseBranch)
+			fmt.Fprintf(os.Stderr, "Use the default branch of the repository as the target branch: %s\n", baseBranch)
 		}
 
 		if headBranch == baseBranch {
@@ -119,12 +128,23 @@ It will prompt for a Title and Description if they are not provided via flags.`,
 			}
 		}
 
+		// Ревьюеры: --reviewer, иначе 'pr.reviewers' и владельцы кода из CODEOWNERS
+		reviewers := splitReviewers(prCreateReviewersFlag)
+		if len(reviewers) == 0 {
+			reviewers = defaultReviewers(cmd.Context(), baseBranch, headBranch)
+		}
+		reviewerIDs, err := resolveReviewers(cmd.Context(), reviewers)
+		if err != nil {
+			return err
+		}
+
 		publishStatus := !prCreateDraftFlag
 		apiBody := api.CreatePullRequestBody{
 			Title:        title,
 			SourceBranch: headBranch,
 			TargetBranch: baseBranch,
 			Description:  body,
+			ReviewerIDs:  reviewerIDs,
 			Publish:      publishStatus,
 		}
 
@@ -132,13 +152,17 @@ It will prompt for a Title and Description if they are not provided via flags.`,
 		if prCreateDraftFlag {
 			statusMsg = "Creating a draft Pull Request..."
 		}
-		fmt.Println(statusMsg)
+		fmt.Fprintln(os.Stderr, statusMsg)
 
-		createdPR, err := apiClient.CreatePullRequest(orgSlug, repoSlug, apiBody)
+		createdPR, err := apiClient.CreatePullRequest(cmd.Context(), orgSlug, repoSlug, apiBody)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(createdPR)
+		}
+
 		fmt.Println("\nPull-Request has been successfully created!")
 		fmt.Printf("ID/Slug:    %s\n", cliutils.DerefString(createdPR.Slug))
 		fmt.Printf("Title:  %s\n", cliutils.DerefString(createdPR.Title))
@@ -156,9 +180,9 @@ func promptForInput(prompt, defaultValue string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 	if defaultValue != "" {
 		cleanDefault := strings.ReplaceAll(strings.Split(defaultValue, "\n")[0], "\r", "")
-		fmt.Printf("%s [%s]: ", prompt, cleanDefault)
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", prompt, cleanDefault)
 	} else {
-		fmt.Printf("%s: ", prompt)
+		fmt.Fprintf(os.Stderr, "%s: ", prompt)
 	}
 	input, err := reader.ReadString('\n')
 	if err != nil {
@@ -173,6 +197,7 @@ func promptForInput(prompt, defaultValue string) (string, error) {
 
 func init() {
 	prCmd.AddCommand(prCreateCmd)
+	supportsStructuredOutput(prCreateCmd)
 
 	prCreateCmd.Flags().StringVarP(&prCreateTitleFlag, "title", "t", "", "Pull-Request Title")
 	prCreateCmd.Flags().StringVarP(&prCreateBodyFlag, "body", "b", "", "Pull-Request Description")
@@ -180,5 +205,6 @@ func init() {
 	prCreateCmd.Flags().StringVarP(&prCreateHeadBranchFlag, "head", "H", "", "Source branch (where to freeze from) (default: current branch)")
 	prCreateCmd.Flags().StringVarP(&prCreateRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo> (default: current repository)")
 	prCreateCmd.Flags().BoolVarP(&prCreateDraftFlag, "draft", "d", false, "Create a Pull Request as a draft")
+	prCreateCmd.Flags().StringSliceVarP(&prCreateReviewersFlag, "reviewer", "r", nil, "Request reviews from these users (logins or IDs, comma-separated; replaces the defaults)")
 
 }
@@ -0,0 +1,190 @@
+// cmd/pr_diff.go
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/git"
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	prDiffRepoFlag     string
+	prDiffNameOnlyFlag bool
+	prDiffStatFlag     bool
+	prDiffColorFlag    string
+)
+
+var prDiffCmd = &cobra.Command{
+	Use:   "diff <pr_id_or_slug>",
+	Short: "Show the changes of a pull request",
+	Long: `Prints the changes of a pull request as a unified diff.
+
+The diff comes from the API. If the server does not provide it, src fetches
+the source and target branches and runs 'git diff <target>...<source>', which
+requires a clone of the repository.
+
+In a terminal the diff is coloured and shown through $PAGER (default: less;
+PAGER=cat turns it off).
+
+Example: src pr diff 12 --stat`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prSlug := args[0]
+		orgSlug, repoSlug, err := prDiffRepo(prDiffRepoFlag)
+		if err != nil {
+			return err
+		}
+		if prDiffNameOnlyFlag && prDiffStatFlag {
+			return errors.New("use either --name-only or --stat, not both")
+		}
+		color, err := diffColorEnabled(prDiffColorFlag)
+		if err != nil {
+			return err
+		}
+
+		diff, err := pullRequestDiff(cmd.Context(), orgSlug, repoSlug, prSlug)
+		if err != nil {
+			return err
+		}
+
+		w, wait := startPager()
+		defer wait()
+		switch {
+		case prDiffNameOnlyFlag:
+			for _, f := range parseUnifiedDiff(diff) {
+				fmt.Fprintln(w, f.Path)
+			}
+		case prDiffStatFlag:
+			writeDiffStat(w, parseUnifiedDiff(diff), color)
+		case color:
+			fmt.Fprint(w, colorizeDiff(diff))
+		default:
+			fmt.Fprint(w, diff)
+		}
+		return nil
+	},
+}
+
+// prDiffRepo returns the repository from --repo or git remote 'origin'.
+func prDiffRepo(repoFlag string) (string, string, error) {
+	if repoFlag != "" {
+		parts := strings.SplitN(repoFlag, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", fmt.Errorf("invalid format for --repo flag: '%s'. Expected format: <org_slug>/<repo_slug>", repoFlag)
+		}
+		return parts[0], parts[1], nil
+	}
+	orgSlug, repoSlug, err := git.GetCurrentRepoOwnerAndNameFromRemote("origin")
+	if err != nil {
+		return "", "", fmt.Errorf("could not detect repository from git remote. Use --repo <org>/<repo> flag or run from within a repository")
+	}
+	return orgSlug, repoSlug, nil
+}
+
+// diffColorEnabled resolves --color: "auto" colours a terminal unless NO_COLOR is set.
+func diffColorEnabled(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto", "":
+		return os.Getenv("NO_COLOR") == "" && stdoutIsTerminal(), nil
+	}
+	return false, fmt.Errorf("invalid value for --color: '%s'. Use auto, always or never", mode)
+}
+
+// pullRequestDiff returns the unified diff of a PR from the API or, if the
+// server does not provide it, from git.
+func pullRequestDiff(ctx context.Context, orgSlug, repoSlug, prSlug string) (string, error) {
+	diff, err := apiClient.GetPullRequestDiff(ctx, orgSlug, repoSlug, prSlug)
+	if err == nil {
+		return diff, nil
+	}
+	if !errors.Is(err, api.ErrNotFound) {
+		return "", err
+	}
+
+	// 404: либо нет PR, либо сервер не отдаёт diff — GetPullRequest различит
+	pr, err := apiClient.GetPullRequest(ctx, orgSlug, repoSlug, prSlug)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(os.Stderr, "The server does not provide the diff, computing it with git...")
+	return gitPullRequestDiff(ctx, pr, orgSlug, repoSlug)
+}
+
+// gitPullRequestDiff fetches the target and source branches of a PR and
+// returns 'git diff <target>...<source>'. A fork is fetched by URL if there is
+// no remote for it; no remote is added.
+func gitPullRequestDiff(ctx context.Context, pr *api.PullRequest, orgSlug, repoSlug string) (string, error) {
+	targetBranch := cliutils.DerefString(pr.TargetBranch)
+	sourceBranch := cliutils.DerefString(pr.SourceBranch)
+	if targetBranch == "" || sourceBranch == "" {
+		return "", errors.New("the API returned no source or target branch for the pull request")
+	}
+
+	targetRemote, err := git.FindRemoteForRepo(orgSlug, repoSlug)
+	if err != nil {
+		return "", err
+	}
+	if targetRemote == "" {
+		return "", fmt.Errorf("no git remote points to %s/%s. Run the command in a clone of the repository", orgSlug, repoSlug)
+	}
+
+	sourceRemote := targetRemote
+	if src := pr.SourceRepository; src != nil && src.Owner != nil {
+		sourceOrg, sourceRepo := cliutils.DerefString(src.Owner.Slug), cliutils.DerefString(src.Slug)
+		if sourceOrg != "" && sourceRepo != "" && !(strings.EqualFold(sourceOrg, orgSlug) && strings.EqualFold(sourceRepo, repoSlug)) {
+			if sourceRemote, err = git.FindRemoteForRepo(sourceOrg, sourceRepo); err != nil {
+				return "", err
+			}
+			if sourceRemote == "" {
+				originURL, _ := git.GetRemoteURL("origin")
+				cloneURL := src.CloneURL
+				if cloneURL == nil {
+					if repoInfo, err := apiClient.GetRepository(ctx, sourceOrg, sourceRepo); err == nil {
+						cloneURL = repoInfo.CloneURL
+					}
+				}
+				if sourceRemote = pickCloneURL(cloneURL, strings.HasPrefix(originURL, "https://")); sourceRemote == "" {
+					return "", fmt.Errorf("no clone URL available for the fork %s/%s", sourceOrg, sourceRepo)
+				}
+			}
+		}
+	}
+
+	// Каждую ветку забираем отдельно и сразу запоминаем FETCH_HEAD
+	fetch := func(remote, branch string) (string, error) {
+		if err := runGitQuiet("fetch", "--quiet", "--no-tags", remote, "refs/heads/"+branch); err != nil {
+			return "", fmt.Errorf("failed to fetch '%s': %w", branch, err)
+		}
+		return git.GetCommitHash("FETCH_HEAD")
+	}
+	base, err := fetch(targetRemote, targetBranch)
+	if err != nil {
+		return "", err
+	}
+	head, err := fetch(sourceRemote, sourceBranch)
+	if err != nil {
+		return "", err
+	}
+	return git.GetDiff(base, head)
+}
+
+func init() {
+	prCmd.AddCommand(prDiffCmd)
+
+	prDiffCmd.Flags().StringVarP(&prDiffRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (default: current directory)")
+	prDiffCmd.Flags().BoolVar(&prDiffNameOnlyFlag, "name-only", false, "Show only the names of the changed files")
+	prDiffCmd.Flags().BoolVar(&prDiffStatFlag, "stat", false, "Show a summary of the changes per file")
+	prDiffCmd.Flags().StringVar(&prDiffColorFlag, "color", "auto", "Colour the output: auto, always or never")
+}
@@ -0,0 +1,188 @@
+// cmd/pr_edit.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/git"
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	prEditRepoFlag            string
+	prEditTitleFlag           string
+	prEditBodyFlag            string
+	prEditBodyFileFlag        string
+	prEditBaseFlag            string
+	prEditReviewersFlag       []string
+	prEditAddReviewersFlag    []string
+	prEditRemoveReviewersFlag []string
+)
+
+var prEditCmd = &cobra.Command{
+	Use:   "edit <pr_id_or_slug> [flags]",
+	Short: "Edit a pull request",
+	Long: `Changes the title, description, target branch or reviewers of a pull request.
+
+Reviewers are given by login (slug) or user ID. --reviewer replaces the whole
+list, --add-reviewer and --remove-reviewer change the current one.
+
+Example: src pr edit 12 --title "New title" --add-reviewer alice --remove-reviewer bob`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prSlug := args[0]
+		var orgSlug, repoSlug string
+		var err error
+
+		if prEditRepoFlag != "" {
+			parts := strings.SplitN(prEditRepoFlag, "/", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid flag format --repo: '%s'. Expected: <org>/<repo>", prEditRepoFlag)
+			}
+			orgSlug = parts[0]
+			repoSlug = parts[1]
+		} else {
+			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
+			if err != nil {
+				return fmt.Errorf("the repository could not be determined. Use the --repo <org>/<repo>")
+			}
+		}
+
+		var body api.UpdatePullRequestBody
+		hasChanges := false
+
+		if cmd.Flags().Changed("title") {
+			if strings.TrimSpace(prEditTitleFlag) == "" {
+				return errors.New("the title cannot be empty")
+			}
+			body.Title = &prEditTitleFlag
+			hasChanges = true
+		}
+		if cmd.Flags().Changed("body") && cmd.Flags().Changed("body-file") {
+			return errors.New("use either --body or --body-file, not both")
+		}
+		if cmd.Flags().Changed("body") {
+			body.Description = &prEditBodyFlag
+			hasChanges = true
+		}
+		if cmd.Flags().Changed("body-file") {
+			description, err := readBodyFile(prEditBodyFileFlag)
+			if err != nil {
+				return err
+			}
+			body.Description = &description
+			hasChanges = true
+		}
+		if cmd.Flags().Changed("base") {
+			body.TargetBranch = &prEditBaseFlag
+			hasChanges = true
+		}
+
+		// Ревьюеры: новый список целиком или изменения текущего
+		replace := cmd.Flags().Changed("reviewer")
+		add := splitReviewers(prEditAddReviewersFlag)
+		remove := splitReviewers(prEditRemoveReviewersFlag)
+		if replace && (len(add) > 0 || len(remove) > 0) {
+			return errors.New("--reviewer replaces the reviewers and cannot be combined with --add-reviewer or --remove-reviewer")
+		}
+		if replace {
+			ids, err := resolveReviewers(cmd.Context(), splitReviewers(prEditReviewersFlag))
+			if err != nil {
+				return err
+			}
+			if ids == nil {
+				ids = []string{} // "[]", а не null: убрать всех ревьюеров
+			}
+			body.ReviewerIDs = &ids
+			hasChanges = true
+		} else if len(add) > 0 || len(remove) > 0 {
+			pr, err := apiClient.GetPullRequest(cmd.Context(), orgSlug, repoSlug, prSlug)
+			if err != nil {
+				return err
+			}
+			addIDs, err := resolveReviewers(cmd.Context(), add)
+			if err != nil {
+				return err
+			}
+			removeIDs, err := resolveReviewers(cmd.Context(), remove)
+			if err != nil {
+				return err
+			}
+			ids := editReviewerIDs(pr.Reviewers, addIDs, removeIDs)
+			body.ReviewerIDs = &ids
+			hasChanges = true
+		}
+
+		if !hasChanges {
+			fmt.Fprintln(os.Stderr, "No flags are specified for the update. Completion.")
+			fmt.Fprintln(os.Stderr, "Use the --title, --body, --body-file, --base, --reviewer, --add-reviewer, --remove-reviewer.")
+			return nil
+		}
+
+		fmt.Fprintf(os.Stderr, "Updating PR #%s in %s/%s...\n", prSlug, orgSlug, repoSlug)
+
+		updatedPR, err := apiClient.UpdatePullRequest(cmd.Context(), orgSlug, repoSlug, prSlug, body)
+		if err != nil {
+			return err
+		}
+
+		if outputOpts.Enabled() {
+			return printStructured(updatedPR)
+		}
+
+		fmt.Println("\nThe pull request has been successfully updated!")
+		fmt.Printf("ID/Slug:     %s\n", cliutils.DerefString(updatedPR.Slug))
+		fmt.Printf("Title:       %s\n", cliutils.DerefString(updatedPR.Title))
+		fmt.Printf("Target Br:   %s\n", cliutils.DerefString(updatedPR.TargetBranch))
+		if len(updatedPR.Reviewers) > 0 {
+			fmt.Printf("Reviewers:   %s\n", formatReviewers(updatedPR.Reviewers))
+		}
+		return nil
+	},
+}
+
+// editReviewerIDs returns the IDs of current plus add minus remove, keeping the order.
+func editReviewerIDs(current []api.PullRequestReviewer, add, remove []string) []string {
+	removed := map[string]bool{}
+	for _, id := range remove {
+		removed[id] = true
+	}
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, r := range current {
+		if r.User == nil {
+			continue
+		}
+		if id := cliutils.DerefString(r.User.ID); id != "" && !removed[id] && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range add {
+		if !removed[id] && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func init() {
+	prCmd.AddCommand(prEditCmd)
+	supportsStructuredOutput(prEditCmd)
+
+	prEditCmd.Flags().StringVarP(&prEditRepoFlag, "repo", "R", "", "Specify a repository <org>/<repo>")
+	prEditCmd.Flags().StringVarP(&prEditTitleFlag, "title", "t", "", "New title")
+	prEditCmd.Flags().StringVarP(&prEditBodyFlag, "body", "b", "", "New description")
+	prEditCmd.Flags().StringVarP(&prEditBodyFileFlag, "body-file", "F", "", "Read the new description from a file (- for stdin)")
+	prEditCmd.Flags().StringVarP(&prEditBaseFlag, "base", "B", "", "New target branch")
+	prEditCmd.Flags().StringSliceVarP(&prEditReviewersFlag, "reviewer", "r", nil, "Replace the reviewers (logins or IDs, comma-separated; '' removes all)")
+	prEditCmd.Flags().StringSliceVar(&prEditAddReviewersFlag, "add-reviewer", nil, "Add reviewers (logins or IDs, comma-separated)")
+	prEditCmd.Flags().StringSliceVar(&prEditRemoveReviewersFlag, "remove-reviewer", nil, "Remove reviewers (logins or IDs, comma-separated)")
+}
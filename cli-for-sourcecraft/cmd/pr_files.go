@@ -0,0 +1,61 @@
+// cmd/pr_files.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var prFilesRepoFlag string
+
+var prFilesCmd = &cobra.Command{
+	Use:   "files <pr_id_or_slug>",
+	Short: "List the files changed by a pull request",
+	Long: `Lists the files changed by a pull request with the number of added and
+deleted lines. The changes are taken from the same diff as 'src pr diff'.
+
+Example: src pr files 12 --json=path,additions`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prSlug := args[0]
+		orgSlug, repoSlug, err := prDiffRepo(prFilesRepoFlag)
+		if err != nil {
+			return err
+		}
+
+		diff, err := pullRequestDiff(cmd.Context(), orgSlug, repoSlug, prSlug)
+		if err != nil {
+			return err
+		}
+		files := parseUnifiedDiff(diff)
+
+		if outputOpts.Enabled() {
+			return printStructured(files)
+		}
+		if len(files) == 0 {
+			fmt.Fprintf(os.Stderr, "PR #%s does not change any files.\n", prSlug)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tADDED\tDELETED\tFILE")
+		for _, f := range files {
+			added, deleted := fmt.Sprintf("+%d", f.Additions), fmt.Sprintf("-%d", f.Deletions)
+			if f.Binary {
+				added, deleted = "bin", "bin"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Status, added, deleted, diffFileName(f))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	prCmd.AddCommand(prFilesCmd)
+	supportsStructuredOutput(prFilesCmd)
+
+	prFilesCmd.Flags().StringVarP(&prFilesRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (default: current directory)")
+}
@@ -11,11 +11,12 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
-	prListRepoFlag string
+	prListRepoFlag  string
+	prListLimitFlag int
+	prListAllFlag   bool
 )
 
 var prListCmd = &cobra.Command{
@@ -25,9 +26,12 @@ var prListCmd = &cobra.Command{
 If no repository is specified with --repo, it uses the current repository based on git remotes.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := listOptions(prListLimitFlag, prListAllFlag)
+		if err != nil {
+			return err
+		}
 
 		var orgSlug, repoSlug string
-		var err error
 
 		if prListRepoFlag != "" {
 			parts := strings.SplitN(prListRepoFlag, "/", 2)
@@ -36,29 +40,33 @@ If no repository is specified with --repo, it uses the current repository based
 			}
 			orgSlug = parts[0]
 			repoSlug = parts[1]
-			fmt.Printf("Listing pull requests for specified repository: %s/%s\n", orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Listing pull requests for specified repository: %s/%s\n", orgSlug, repoSlug)
 		} else {
-			fmt.Println("Attempting to detect repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Attempting to detect repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not detect repository from git remote and 'organization' not set in config. Use --repo <org>/<repo> flag or run from within a repository")
 				}
 				return fmt.Errorf("could not detect repository slug from git remote. Use --repo <org>/<repo> flag or run from within a repository")
 
 			}
-			fmt.Printf("Detected repository: %s/%s\n", orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Detected repository: %s/%s\n", orgSlug, repoSlug)
 		}
 
-		fmt.Printf("Fetching pull requests for %s/%s...\n", orgSlug, repoSlug)
-		prs, err := apiClient.ListPullRequests(orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Fetching pull requests for %s/%s...\n", orgSlug, repoSlug)
+		prs, err := apiClient.ListPullRequests(cmd.Context(), orgSlug, repoSlug, opts)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(prs)
+		}
+
 		if len(prs) == 0 {
-			fmt.Println("No pull requests found.")
+			fmt.Fprintln(os.Stderr, "No pull requests found.")
 			return nil
 		}
 
@@ -99,5 +107,7 @@ If no repository is specified with --repo, it uses the current repository based
 
 func init() {
 	prCmd.AddCommand(prListCmd)
+	supportsStructuredOutput(prListCmd)
+	addListFlags(prListCmd, &prListLimitFlag, &prListAllFlag)
 	prListCmd.Flags().StringVarP(&prListRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format")
 }
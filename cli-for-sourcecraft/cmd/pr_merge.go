@@ -3,13 +3,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 
+	"cli-for-sourcecraft/internal/api"
 	"cli-for-sourcecraft/internal/git"
 	cliutils "cli-for-sourcecraft/internal/utils"
 
-	"cli-for-sourcecraft/internal/api" // Нужно для MergeParameters
-
 	"github.com/spf13/cobra"
 )
 
@@ -19,12 +20,39 @@ var (
 	prMergeSquashFlag       bool // --squash
 	prMergeRebaseFlag       bool // --rebase
 	prMergeDeleteBranchFlag bool // --delete-branch
+	prMergeAutoFlag         bool // --auto
 )
 
+// prMergeResult — результат 'src pr merge' для --json/--jq/--template.
+type prMergeResult struct {
+	PullRequest        string `json:"pull_request"`
+	Decision           string `json:"decision,omitempty"` // Пусто для --auto: решение не создается
+	Strategy           string `json:"strategy"`
+	Status             string `json:"status,omitempty"`
+	MergeCommit        string `json:"merge_commit,omitempty"`
+	LocalBranchDeleted bool   `json:"local_branch_deleted"`
+}
+
 var prMergeCmd = &cobra.Command{
 	Use:   "merge <pr_id_or_slug>",
 	Short: "Merge a pull request into its target branch",
-	Long: `Merges a pull request. This requires the PR to be approved and ready for merge.
+	Long: `Merges a pull request into its target branch with a merge commit, or with
+--squash / --rebase.
+
+Without --auto the pull request is merged right away; the server refuses if it
+is not ready (failed checks, missing approvals, conflicts). The API merges
+through the review decision endpoint, so the merge is also recorded as your
+approval.
+
+With --auto nothing is approved: the strategy is saved on the pull request, and
+the server merges it with that strategy once it has the required approvals and
+all checks pass. Approve separately with 'src pr review --approve' if you are
+a reviewer.
+
+--delete-branch deletes the source branch on the server and, after an immediate
+merge, the local branch of the same name too. The local branch is kept if it has
+commits that are not in the pushed branch or the merge result. If that branch
+is checked out, src switches to the target branch first.
 
 Example: src pr merge 1 --squash --delete-branch`,
 	Args: cobra.ExactArgs(1), // Требуем <pr_id_or_slug>
@@ -48,70 +76,167 @@ Example: src pr merge 1 --squash --delete-branch`,
 				return fmt.Errorf("could not detect repository from git remote. Use --repo <org>/<repo> flag or run from within a repository")
 			}
 		}
+		// Локальные шаги (merge commit, удаление ветки) — только в рабочей копии этого репозитория
+		originOrg, originRepo, _ := git.GetCurrentRepoOwnerAndNameFromRemote("origin")
+		inWorkingCopy := originOrg == orgSlug && originRepo == repoSlug
 
-		// *** ИЗМЕНЕНИЕ: ***
-		// Проверка на конфликты стратегий ИЛИ ЛЮБОЙ ИЗ ФЛАГОВ
 		if prMergeSquashFlag && prMergeRebaseFlag {
 			return fmt.Errorf("cannot use --squash and --rebase together. Choose one.")
 		}
 
-		// *** ДОБАВЛЕНО: Предупреждение, если API не поддерживает флаги ***
-		if prMergeSquashFlag || prMergeRebaseFlag || prMergeDeleteBranchFlag {
-			fmt.Println("---------------------------------------------------------------------")
-			fmt.Println("ВНИМАНИЕ: Флаги --squash, --rebase и --delete-branch")
-			fmt.Println("не поддерживаются текущей спецификацией API (Swagger).")
-			fmt.Println("Команда попытается 'одобрить' (approve) PR, но")
-			fmt.Println("параметры слияния будут проигнорированы сервером.")
-			fmt.Println("---------------------------------------------------------------------")
-		}
-
 		// 2. Готовим параметры слияния
-		// *ПРИМЕЧАНИЕ*: Мы все еще передаем их в apiClient,
-		// но (согласно нашему исправлению в client.go) они будут проигнорированы.
 		mergeParams := api.MergeParameters{
 			Squash:       prMergeSquashFlag,
 			Rebase:       prMergeRebaseFlag,
 			DeleteBranch: prMergeDeleteBranchFlag,
 		}
+		strategy := "merge"
+		if prMergeSquashFlag {
+			strategy = "squash"
+		} else if prMergeRebaseFlag {
+			strategy = "rebase"
+		}
 
-		// 3. Вызываем API
-		// *** ИЗМЕНЕНИЕ: Текст сообщения, так как мы 'одобряем', а не 'мержим' ***
-		fmt.Printf("Attempting to 'approve' (merge) PR #%s in %s/%s...\n", prSlug, orgSlug, repoSlug)
+		result := prMergeResult{PullRequest: prSlug, Strategy: strategy}
 
-		// *** ИЗМЕНЕНИЕ: ***
-		// apiClient.MergePullRequest ТЕПЕРЬ ВОЗВРАЩАЕТ (*SetDecisionResponse, error)
-		//
-		decisionResponse, err := apiClient.MergePullRequest(orgSlug, repoSlug, prSlug, mergeParams)
-		if err != nil {
-			return err // Ошибка API (например, 404, если PR не найден)
+		// 3a. --auto: только сохраняем стратегию в PR, без решения (не одобряем за пользователя)
+		if prMergeAutoFlag {
+			fmt.Fprintf(os.Stderr, "Enabling auto-merge (%s) for PR #%s in %s/%s...\n", strategy, prSlug, orgSlug, repoSlug)
+			pr, err := apiClient.UpdatePullRequest(cmd.Context(), orgSlug, repoSlug, prSlug, api.UpdatePullRequestBody{MergeParameters: &mergeParams})
+			if err != nil {
+				return fmt.Errorf("failed to enable auto-merge for PR #%s: %w", prSlug, err)
+			}
+			result.Status = cliutils.DerefString(pr.Status)
+			if outputOpts.Enabled() {
+				return printStructured(result)
+			}
+			fmt.Printf("PR #%s will be merged (%s) once it is approved and all checks pass.\n", prSlug, strategy)
+			if prMergeDeleteBranchFlag {
+				fmt.Println("The source branch will be deleted on the server after the merge; delete your local branch yourself.")
+			}
+			return nil
 		}
 
-		// 4. Выводим результат
-		// *** ИЗМЕНЕНИЕ: Мы больше не получаем 'mergedPR.Status' ***
-		// Мы получаем 'decisionResponse.CreatedDecision'
+		// 3b. Слияние сейчас
+		fmt.Fprintf(os.Stderr, "Merging PR #%s in %s/%s (%s)...\n", prSlug, orgSlug, repoSlug, strategy)
+		decisionResponse, err := apiClient.MergePullRequest(cmd.Context(), orgSlug, repoSlug, prSlug, mergeParams)
+		if err != nil {
+			return fmt.Errorf("failed to merge PR #%s: %w", prSlug, err)
+		}
+		result.Decision = cliutils.DerefString(decisionResponse.CreatedDecision)
 
-		newDecision := cliutils.DerefString(decisionResponse.CreatedDecision)
+		// 4. Статус PR после слияния и merge commit
+		pr, err := apiClient.GetPullRequest(cmd.Context(), orgSlug, repoSlug, prSlug)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: merged, but could not re-read PR #%s: %v\n", prSlug, err)
+			pr = &api.PullRequest{}
+		}
+		result.Status = cliutils.DerefString(pr.Status)
+		targetBranch := cliutils.DerefString(pr.TargetBranch)
+		sourceBranch := cliutils.DerefString(pr.SourceBranch)
+		merged := result.Status == "" || strings.EqualFold(result.Status, "merged")
+
+		if merged && inWorkingCopy && targetBranch != "" {
+			// После слияния вершина целевой ветки — это merge commit (или последний коммит squash/rebase)
+			if err := runGitQuiet("fetch", "origin", targetBranch); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not fetch '%s' to find the merge commit: %v\n", targetBranch, err)
+			} else if hash, err := git.GetShortCommitHash("FETCH_HEAD"); err == nil {
+				result.MergeCommit = hash
+			}
+		}
 
-		fmt.Println("\nAPI request complete.")
-		fmt.Printf("PR #%s decision set to: %s\n", prSlug, newDecision)
+		// 5. Удаляем локальную ветку
+		if merged && prMergeDeleteBranchFlag && inWorkingCopy && sourceBranch != "" && sourceBranch != targetBranch && git.BranchExists(sourceBranch) {
+			if err := deleteMergedLocalBranch(sourceBranch, targetBranch, result.MergeCommit != ""); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				result.LocalBranchDeleted = true
+			}
+		}
 
-		if newDecision == "approve" {
-			fmt.Println("SUCCESS: Pull Request 'approve' decision was set.")
-			fmt.Println("Сервер должен автоматически запустить слияние, если все проверки пройдены.")
-		} else {
-			fmt.Printf("INFO: API returned decision '%s'.\n", newDecision)
+		if outputOpts.Enabled() {
+			return printStructured(result)
 		}
 
+		if !merged {
+			fmt.Printf("The merge of PR #%s was accepted; its status is now '%s'.\n", prSlug, result.Status)
+			return nil
+		}
+		into := ""
+		if targetBranch != "" {
+			into = " into " + targetBranch
+		}
+		fmt.Printf("Merged PR #%s%s (%s).\n", prSlug, into, strategy)
+		if result.MergeCommit != "" {
+			title, _ := git.GetLastCommitTitle("FETCH_HEAD")
+			fmt.Printf("Merge commit: %s %s\n", result.MergeCommit, title)
+		}
+		if prMergeDeleteBranchFlag && sourceBranch != "" {
+			fmt.Printf("Deleted branch '%s' on the server", sourceBranch)
+			if result.LocalBranchDeleted {
+				fmt.Print(" and locally")
+			}
+			fmt.Println(".")
+		}
 		return nil
 	},
 }
 
+// deleteMergedLocalBranch deletes the local source branch of a merged PR. If
+// it is checked out, the target branch is checked out and fast-forwarded to
+// the fetched merge result first. -D is needed: after a squash or rebase merge
+// git does not see the branch as merged, so it is first checked that the local
+// tip is part of the pushed branch (origin/<source>) or of the merge result.
+func deleteMergedLocalBranch(sourceBranch, targetBranch string, fetched bool) error {
+	localRef := "refs/heads/" + sourceBranch
+	pushed := git.IsAncestor(localRef, "refs/remotes/origin/"+sourceBranch)
+	if !pushed && fetched {
+		pushed = git.IsAncestor(localRef, "FETCH_HEAD")
+	}
+	if !pushed {
+		return fmt.Errorf("local branch '%s' has commits that are not in the merged pull request; keeping it. Delete it with 'git branch -D %s' if they are not needed", sourceBranch, sourceBranch)
+	}
+
+	current, _ := git.GetCurrentBranchName()
+	if current == sourceBranch {
+		if targetBranch == "" {
+			return fmt.Errorf("branch '%s' is checked out and the target branch is unknown; not deleting it", sourceBranch)
+		}
+		fmt.Fprintf(os.Stderr, "Switching to '%s'...\n", targetBranch)
+		if err := runGitQuiet("checkout", targetBranch); err != nil {
+			return fmt.Errorf("could not switch to '%s', local branch '%s' kept: %w", targetBranch, sourceBranch, err)
+		}
+		if fetched {
+			if err := runGitQuiet("merge", "--ff-only", "FETCH_HEAD"); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not fast-forward '%s': %v\n", targetBranch, err)
+			}
+		}
+	}
+	if err := runGitQuiet("branch", "-D", sourceBranch); err != nil {
+		return fmt.Errorf("could not delete local branch '%s': %w", sourceBranch, err)
+	}
+	return nil
+}
+
+// runGitQuiet runs git without printing its output; the output is returned in
+// the error. Unlike runGitCommand it keeps stdout clean for --json.
+func runGitQuiet(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Env = gitEnv()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 func init() {
 	prCmd.AddCommand(prMergeCmd) // Добавляем 'merge' к 'pr'
+	supportsStructuredOutput(prMergeCmd)
 
 	// Добавляем флаги
 	prMergeCmd.Flags().StringVarP(&prMergeRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (default: current directory)")
-	prMergeCmd.Flags().BoolVar(&prMergeSquashFlag, "squash", false, "Use squash merge strategy (NOTE: Not supported by current API)")
-	prMergeCmd.Flags().BoolVar(&prMergeRebaseFlag, "rebase", false, "Use rebase merge strategy (NOTE: Not supported by current API)")
-	prMergeCmd.Flags().BoolVar(&prMergeDeleteBranchFlag, "delete-branch", false, "Delete the source branch after a successful merge (NOTE: Not supported by current API)")
+	prMergeCmd.Flags().BoolVar(&prMergeSquashFlag, "squash", false, "Squash the commits into one commit on the target branch")
+	prMergeCmd.Flags().BoolVar(&prMergeRebaseFlag, "rebase", false, "Rebase the commits onto the target branch")
+	prMergeCmd.Flags().BoolVar(&prMergeDeleteBranchFlag, "delete-branch", false, "Delete the source branch on the server and locally after the merge")
+	prMergeCmd.Flags().BoolVar(&prMergeAutoFlag, "auto", false, "Save the strategy and let the server merge the pull request once it is approved and checks pass (does not approve)")
 }
@@ -0,0 +1,197 @@
+// cmd/pr_review.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+	"cli-for-sourcecraft/internal/git"
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Flags for pr review
+var (
+	prReviewRepoFlag           string
+	prReviewApproveFlag        bool
+	prReviewRequestChangesFlag bool
+	prReviewCommentFlag        bool
+	prReviewResetFlag          bool
+	prReviewBodyFlag           string
+	prReviewBodyFileFlag       string
+)
+
+// prReviewResult — результат 'src pr review' для --json/--jq/--template.
+type prReviewResult struct {
+	PullRequest string `json:"pull_request"`
+	Decision    string `json:"decision,omitempty"` // "approve", "block" или "reset"
+	CommentID   string `json:"comment_id,omitempty"`
+}
+
+var prReviewCmd = &cobra.Command{
+	Use:   "review <pr_id_or_slug>",
+	Short: "Approve, block or comment on a pull request",
+	Long: `Adds your review to a pull request. Choose exactly one action:
+
+  --approve          approve the pull request
+  --request-changes  block the merge until you change the decision
+  --comment          only leave a comment
+  --reset            remove your decision
+
+The review summary is given with --body, --body-file (- reads stdin) or, for
+--request-changes and --comment in a terminal, written in your editor
+($VISUAL, $EDITOR or git's core.editor). It is posted as a comment.
+
+--approve sends only the decision, without merge parameters, so it does not
+merge the pull request by itself: use 'src pr merge' for that. If auto-merge
+was enabled with 'src pr merge --auto', the server merges the pull request once
+it has the required approvals and all checks pass.
+
+Example: src pr review 12 --request-changes --body "Please add tests"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prSlug := args[0]
+		var orgSlug, repoSlug string
+		var err error
+
+		// 1. Определяем Репозиторий
+		if prReviewRepoFlag != "" {
+			parts := strings.SplitN(prReviewRepoFlag, "/", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid format for --repo flag: '%s'. Expected format: <org_slug>/<repo_slug>", prReviewRepoFlag)
+			}
+			orgSlug = parts[0]
+			repoSlug = parts[1]
+		} else {
+			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
+			if err != nil {
+				return fmt.Errorf("could not detect repository from git remote. Use --repo <org>/<repo> flag or run from within a repository")
+			}
+		}
+
+		// 2. Проверяем действие
+		actions := 0
+		for _, set := range []bool{prReviewApproveFlag, prReviewRequestChangesFlag, prReviewCommentFlag, prReviewResetFlag} {
+			if set {
+				actions++
+			}
+		}
+		if actions != 1 {
+			return errors.New("specify exactly one of --approve, --request-changes, --comment or --reset")
+		}
+
+		// 3. Текст ревью
+		body, err := prReviewBody(prSlug)
+		if err != nil {
+			return err
+		}
+		if prReviewCommentFlag && body == "" {
+			return errors.New("--comment needs a review body: use --body, --body-file or write it in the editor")
+		}
+
+		// 4. Решение, затем комментарий
+		result := prReviewResult{PullRequest: prSlug}
+		switch {
+		case prReviewApproveFlag, prReviewRequestChangesFlag:
+			decision := api.DecisionApprove
+			if prReviewRequestChangesFlag {
+				decision = api.DecisionBlock
+			}
+			fmt.Fprintf(os.Stderr, "Setting decision '%s' on PR #%s in %s/%s...\n", decision, prSlug, orgSlug, repoSlug)
+			resp, err := apiClient.SetPullRequestDecision(cmd.Context(), orgSlug, repoSlug, prSlug, decision)
+			if err != nil {
+				return fmt.Errorf("failed to review PR #%s: %w", prSlug, err)
+			}
+			result.Decision = cliutils.DerefString(resp.CreatedDecision)
+			if result.Decision == "" {
+				result.Decision = decision
+			}
+		case prReviewResetFlag:
+			fmt.Fprintf(os.Stderr, "Removing your decision on PR #%s in %s/%s...\n", prSlug, orgSlug, repoSlug)
+			if err := apiClient.ResetPullRequestDecision(cmd.Context(), orgSlug, repoSlug, prSlug); err != nil {
+				return fmt.Errorf("failed to reset your decision on PR #%s: %w", prSlug, err)
+			}
+			result.Decision = "reset"
+		}
+
+		if body != "" {
+			comment, err := apiClient.CreatePullRequestComment(cmd.Context(), orgSlug, repoSlug, prSlug, body)
+			if err != nil {
+				if result.Decision != "" {
+					return fmt.Errorf("decision '%s' was set, but the review comment could not be posted: %w", result.Decision, err)
+				}
+				return fmt.Errorf("failed to comment on PR #%s: %w", prSlug, err)
+			}
+			result.CommentID = cliutils.DerefString(comment.ID)
+		}
+
+		if outputOpts.Enabled() {
+			return printStructured(result)
+		}
+
+		switch {
+		case prReviewApproveFlag:
+			fmt.Printf("Approved PR #%s.\n", prSlug)
+		case prReviewRequestChangesFlag:
+			fmt.Printf("Requested changes on PR #%s: the merge is blocked until you change your decision.\n", prSlug)
+		case prReviewResetFlag:
+			fmt.Printf("Removed your decision on PR #%s.\n", prSlug)
+		default:
+			fmt.Printf("Commented on PR #%s.\n", prSlug)
+		}
+		return nil
+	},
+}
+
+// prReviewBody returns the review summary from --body, --body-file or the
+// editor. The editor is only opened for --request-changes and --comment, and
+// only in a terminal.
+func prReviewBody(prSlug string) (string, error) {
+	if prReviewBodyFlag != "" && prReviewBodyFileFlag != "" {
+		return "", errors.New("use either --body or --body-file, not both")
+	}
+	if prReviewBodyFileFlag != "" {
+		return readBodyFile(prReviewBodyFileFlag)
+	}
+	if prReviewBodyFlag != "" || prReviewApproveFlag || prReviewResetFlag {
+		return strings.TrimSpace(prReviewBodyFlag), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", nil
+	}
+	return editText("", fmt.Sprintf("Review summary for PR #%s.\nLines starting with '#' are ignored.", prSlug))
+}
+
+// readBodyFile reads a text for --body-file; "-" reads standard input.
+func readBodyFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read body from '%s': %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func init() {
+	prCmd.AddCommand(prReviewCmd)
+	supportsStructuredOutput(prReviewCmd)
+
+	prReviewCmd.Flags().StringVarP(&prReviewRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (default: current directory)")
+	prReviewCmd.Flags().BoolVarP(&prReviewApproveFlag, "approve", "a", false, "Approve the pull request (does not merge it)")
+	prReviewCmd.Flags().BoolVarP(&prReviewRequestChangesFlag, "request-changes", "r", false, "Request changes: block the merge")
+	prReviewCmd.Flags().BoolVarP(&prReviewCommentFlag, "comment", "c", false, "Only comment, without a decision")
+	prReviewCmd.Flags().BoolVar(&prReviewResetFlag, "reset", false, "Remove your decision")
+	prReviewCmd.Flags().StringVarP(&prReviewBodyFlag, "body", "b", "", "Review summary")
+	prReviewCmd.Flags().StringVarP(&prReviewBodyFileFlag, "body-file", "F", "", "Read the review summary from a file (- for stdin)")
+}
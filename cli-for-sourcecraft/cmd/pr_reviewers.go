@@ -0,0 +1,235 @@
+// cmd/pr_reviewers.go
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"cli-for-sourcecraft/internal/git"
+	cliutils "cli-for-sourcecraft/internal/utils"
+)
+
+// codeownersFiles — где ищется файл владельцев кода, относительно корня рабочей копии.
+var codeownersFiles = []string{".sourcecraft/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// userIDPattern matches user IDs (UUIDs), which are used as they are.
+var userIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// resolveReviewers turns user slugs (with or without '@') and IDs into user
+// IDs, looking the slugs up through the API. Duplicates are dropped.
+func resolveReviewers(ctx context.Context, reviewers []string) ([]string, error) {
+	seen := map[string]bool{}
+	var ids []string
+	for _, reviewer := range reviewers {
+		reviewer = strings.TrimPrefix(strings.TrimSpace(reviewer), "@")
+		if reviewer == "" {
+			continue
+		}
+		id := reviewer
+		if !userIDPattern.MatchString(reviewer) {
+			user, err := apiClient.GetUser(ctx, reviewer)
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve reviewers: %w", err)
+			}
+			id = cliutils.DerefString(user.ID)
+			if id == "" {
+				return nil, fmt.Errorf("could not resolve reviewer '%s': the API returned no user ID", reviewer)
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// splitReviewers splits the values of a repeated comma-separated flag.
+func splitReviewers(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+// codeownersRule is one line of a CODEOWNERS file.
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// findCodeowners returns the CODEOWNERS file of the current working copy ("" if there is none).
+func findCodeowners() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	_, gitRoot := findRepoConfig(cwd)
+	if gitRoot == "" {
+		return ""
+	}
+	for _, name := range codeownersFiles {
+		path := filepath.Join(gitRoot, filepath.FromSlash(name))
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// readCodeowners parses a CODEOWNERS file: "<pattern> @user1 @user2" per
+// line, '#' starts a comment. Teams (@org/team) and e-mail addresses cannot be
+// requested as reviewers and are skipped with a warning.
+func readCodeowners(path string) ([]codeownersRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	var rules []codeownersRule
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		pattern, err := codeownersPattern(fields[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s:%d: invalid pattern '%s': %v\n", path, lineNo, fields[0], err)
+			continue
+		}
+		rule := codeownersRule{pattern: pattern}
+		for _, owner := range fields[1:] {
+			slug, ok := strings.CutPrefix(owner, "@")
+			if !ok || strings.Contains(slug, "/") {
+				fmt.Fprintf(os.Stderr, "Warning: %s:%d: '%s' is not a user (@login) and is skipped\n", path, lineNo, owner)
+				continue
+			}
+			rule.owners = append(rule.owners, slug)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	return rules, nil
+}
+
+// codeownersPattern converts a CODEOWNERS (gitignore-style) pattern into a
+// regexp matched against slash-separated paths from the repository root:
+//   - a pattern with a slash at the start or in the middle is anchored at the root,
+//     otherwise it matches at any depth;
+//   - '*' and '?' do not cross '/', '**' does;
+//   - a pattern that matches a directory also matches everything under it.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var re strings.Builder
+	if anchored {
+		re.WriteString("^")
+	} else {
+		re.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			re.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			re.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	if strings.HasSuffix(pattern, "/") {
+		re.WriteString("/.*$")
+	} else {
+		re.WriteString("(/.*)?$")
+	}
+	return regexp.Compile(re.String())
+}
+
+// codeownersFor returns the owners of files: for every file the last matching
+// rule wins, like in git and other forges.
+func codeownersFor(rules []codeownersRule, files []string) []string {
+	seen := map[string]bool{}
+	var owners []string
+	for _, file := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !rules[i].pattern.MatchString(file) {
+				continue
+			}
+			for _, owner := range rules[i].owners {
+				if !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// defaultReviewers returns the reviewers of a new pull request when none are
+// given with --reviewer: 'pr.reviewers' plus the code owners of the changed
+// files. The author (the current user) is never requested.
+func defaultReviewers(ctx context.Context, baseBranch, headBranch string) []string {
+	reviewers := repoSettingList("pr.reviewers")
+
+	path := findCodeowners()
+	if path == "" {
+		return reviewers
+	}
+	rules, err := readCodeowners(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return reviewers
+	}
+	files, err := git.GetChangedFiles(baseBranch, headBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: code owners are not requested for review: %v\n", err)
+		return reviewers
+	}
+	owners := codeownersFor(rules, files)
+	if len(owners) == 0 {
+		return reviewers
+	}
+
+	author := ""
+	if me, _, err := apiClient.GetCurrentUser(ctx); err == nil {
+		author = cliutils.DerefString(me.Slug)
+	}
+	var requested []string
+	for _, owner := range owners {
+		if !strings.EqualFold(owner, author) {
+			requested = append(requested, owner)
+		}
+	}
+	if len(requested) > 0 {
+		fmt.Fprintf(os.Stderr, "Requesting review from code owners (%s): %s\n", path, strings.Join(requested, ", "))
+	}
+	return append(reviewers, requested...)
+}
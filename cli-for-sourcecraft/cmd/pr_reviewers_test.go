@@ -0,0 +1,135 @@
+// cmd/pr_reviewers_test.go
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+func TestCodeownersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"*", []string{"README.md", "cmd/root.go", "a/b/c"}, nil},
+		{"*.go", []string{"main.go", "cmd/root.go", "a/b/c.go"}, []string{"main.go.txt", "go", "cmd/root.gox"}},
+		{"/docs/", []string{"docs/readme.md", "docs/a/b.md"}, []string{"docs", "src/docs/readme.md", "docsx/a"}},
+		{"docs/", []string{"docs/readme.md", "src/docs/readme.md"}, []string{"docs", "mydocs/a"}},
+		{"/build", []string{"build", "build/out.bin"}, []string{"src/build", "builder"}},
+		{"cmd/root.go", []string{"cmd/root.go"}, []string{"x/cmd/root.go", "cmd/root.go.bak"}},
+		{"a/**/b", []string{"a/b", "a/x/b", "a/x/y/b", "a/x/b/c.txt"}, []string{"b", "x/a/b", "a/bb"}},
+		{"**/x", []string{"x", "a/x", "a/b/x", "a/x/file"}, []string{"xy", "a/xy"}},
+		{"internal/**", []string{"internal/api/client.go", "internal/a"}, []string{"internal", "x/internal/a"}},
+		{"?.txt", []string{"a.txt", "d/b.txt"}, []string{"ab.txt", ".txt"}},
+		{"v1.0", []string{"v1.0"}, []string{"v1x0"}},
+	}
+	for _, tt := range tests {
+		re, err := codeownersPattern(tt.pattern)
+		if err != nil {
+			t.Errorf("codeownersPattern(%q) error = %v", tt.pattern, err)
+			continue
+		}
+		for _, path := range tt.match {
+			if !re.MatchString(path) {
+				t.Errorf("pattern %q (%s) does not match %q", tt.pattern, re, path)
+			}
+		}
+		for _, path := range tt.noMatch {
+			if re.MatchString(path) {
+				t.Errorf("pattern %q (%s) matches %q", tt.pattern, re, path)
+			}
+		}
+	}
+}
+
+func TestCodeownersFor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CODEOWNERS")
+	content := `# Владельцы по умолчанию
+*             @alice
+*.go          @bob @carol   # Go-код
+/docs/        @dave
+docs/api.md   @org/writers writer@example.com
+/cmd/         @erin
+/cmd/root.go  @bob
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := readCodeowners(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 6 {
+		t.Fatalf("%d rules, want 6 (comments and blank lines skipped)", len(rules))
+	}
+	if len(rules[3].owners) != 0 {
+		t.Errorf("team and e-mail owners = %v, want them skipped", rules[3].owners)
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{"default owner", []string{"README.md"}, []string{"alice"}},
+		{"later rule overrides", []string{"internal/api/client.go"}, []string{"bob", "carol"}},
+		{"directory rule", []string{"docs/guide.md"}, []string{"dave"}},
+		{"rule with only team owners leaves the file unowned", []string{"docs/api.md"}, nil},
+		{"last of several matching rules", []string{"cmd/api.go", "cmd/root.go"}, []string{"erin", "bob"}},
+		{"owners are not repeated", []string{"a.go", "b.go", "README.md"}, []string{"bob", "carol", "alice"}},
+		{"no files", nil, nil},
+	}
+	for _, tt := range tests {
+		got := codeownersFor(rules, tt.files)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: codeownersFor(%v) = %v, want %v", tt.name, tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestResolveReviewers(t *testing.T) {
+	const aliceID = "11111111-2222-3333-4444-555555555555"
+	const bobID = "AAAAAAAA-bbbb-cccc-dddd-eeeeeeeeeeee"
+	var lookups []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups = append(lookups, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/alice":
+			w.Write([]byte(`{"id": "` + aliceID + `", "slug": "alice"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	defer srv.Close()
+	oldClient := apiClient
+	apiClient = api.NewClient(srv.URL, "test-token")
+	apiClient.HTTPClient = srv.Client()
+	defer func() { apiClient = oldClient }()
+
+	got, err := resolveReviewers(context.Background(), []string{bobID, "@alice", " alice ", aliceID, "", bobID})
+	if err != nil {
+		t.Fatalf("resolveReviewers() error = %v", err)
+	}
+	if want := []string{bobID, aliceID}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("resolveReviewers() = %v, want %v: IDs unchanged, duplicates dropped", got, want)
+	}
+	for _, path := range lookups {
+		if path != "/users/alice" {
+			t.Errorf("looked up %s, want only logins to be looked up", path)
+		}
+	}
+
+	if _, err := resolveReviewers(context.Background(), []string{"nobody"}); err == nil || !strings.Contains(err.Error(), "nobody") {
+		t.Errorf("unknown login: error = %v, want one naming the login", err)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -40,23 +41,27 @@ If the repository is not specified with --repo, it uses the current git reposito
 			}
 			orgSlug = parts[0]
 			repoSlug = parts[1]
-			fmt.Printf("Viewing PR %s in specified repository: %s/%s\n", prSlug, orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Viewing PR %s in specified repository: %s/%s\n", prSlug, orgSlug, repoSlug)
 		} else {
 			// Пытаемся получить из текущей директории git
-			fmt.Println("Attempting to detect repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Attempting to detect repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
 				return fmt.Errorf("could not detect repository from git remote. Use --repo <org>/<repo> flag or run from within a repository")
 			}
-			fmt.Printf("Viewing PR %s in detected repository: %s/%s\n", prSlug, orgSlug, repoSlug)
+			fmt.Fprintf(os.Stderr, "Viewing PR %s in detected repository: %s/%s\n", prSlug, orgSlug, repoSlug)
 		}
 
 		// 2. Вызываем API для получения PR
-		pr, err := apiClient.GetPullRequest(orgSlug, repoSlug, prSlug)
+		pr, err := apiClient.GetPullRequest(cmd.Context(), orgSlug, repoSlug, prSlug)
 		if err != nil {
 			return err // Ошибка 404 или другая будет выведена
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(pr)
+		}
+
 		// 3. Выводим информацию красиво
 		fmt.Println("--- Pull Request Details ---")
 		fmt.Printf("Title:       %s\n", cliutils.DerefString(pr.Title))
@@ -67,6 +72,9 @@ If the repository is not specified with --repo, it uses the current git reposito
 		fmt.Printf("Source Br:   %s\n", cliutils.DerefString(pr.SourceBranch))
 		fmt.Printf("Target Br:   %s\n", cliutils.DerefString(pr.TargetBranch))
 		fmt.Printf("Author:      %s\n", getAuthor(pr))
+		if len(pr.Reviewers) > 0 {
+			fmt.Printf("Reviewers:   %s\n", formatReviewers(pr.Reviewers))
+		}
 
 		// Форматирование даты
 		if pr.UpdatedAt != nil {
@@ -105,6 +113,26 @@ func getAuthor(pr *api.PullRequest) string {
 	return "-"
 }
 
+// formatReviewers - "alice (approve), bob (block), carol (no decision)"
+func formatReviewers(reviewers []api.PullRequestReviewer) string {
+	parts := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		name := "-"
+		if r.User != nil {
+			name = cliutils.DerefString(r.User.Slug)
+			if name == "" {
+				name = cliutils.DerefString(r.User.ID)
+			}
+		}
+		decision := cliutils.DerefString(r.Decision)
+		if decision == "" {
+			decision = "no decision"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, decision))
+	}
+	return strings.Join(parts, ", ")
+}
+
 // formatTimeAgo - хелпер для форматирования времени (дубликат formatRelativeTime)
 func formatTimeAgo(t time.Time) string {
 	duration := time.Since(t)
@@ -119,6 +147,7 @@ func formatTimeAgo(t time.Time) string {
 
 func init() {
 	prCmd.AddCommand(prViewCmd) // Добавляем 'view' к 'pr'
+	supportsStructuredOutput(prViewCmd)
 	// Добавляем флаг --repo
 	prViewCmd.Flags().StringVarP(&prViewRepoFlag, "repo", "R", "", "Specify repository in <org>/<repo> format (default: current directory)")
 
@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -39,7 +38,7 @@ var repoCloneCmd = &cobra.Command{
 		var repoSlugForDir string
 
 		if strings.HasPrefix(repoIdentifier, "https://") || strings.HasPrefix(repoIdentifier, "git@") {
-			fmt.Println("Using provided URL:", repoIdentifier)
+			fmt.Fprintln(os.Stderr, "Using provided URL:", repoIdentifier)
 			cloneURL = repoIdentifier
 			parts := strings.Split(strings.TrimSuffix(repoIdentifier, ".git"), "/")
 			if len(parts) > 0 {
@@ -47,7 +46,7 @@ var repoCloneCmd = &cobra.Command{
 			}
 
 		} else {
-			orgSlug := viper.GetString("organization")
+			orgSlug := defaultOrganization()
 			repoSlug := repoIdentifier
 
 			if strings.Contains(repoIdentifier, "/") {
@@ -57,12 +56,12 @@ var repoCloneCmd = &cobra.Command{
 				}
 				orgSlug = parts[0]
 				repoSlug = parts[1]
-				fmt.Printf("Cloning repository '%s' from organization '%s'...\n", repoSlug, orgSlug)
+				fmt.Fprintf(os.Stderr, "Cloning repository '%s' from organization '%s'...\n", repoSlug, orgSlug)
 			} else {
 				if orgSlug == "" {
 					return fmt.Errorf("error: 'organization' slug not found in config.yaml. Provide full slug <org>/<repo> or set organization in config")
 				}
-				fmt.Printf("Cloning repository '%s' from organization '%s' (from config)...\n", repoSlug, orgSlug)
+				fmt.Fprintf(os.Stderr, "Cloning repository '%s' from organization '%s' (from config)...\n", repoSlug, orgSlug)
 			}
 
 			if repoSlug == "" {
@@ -70,7 +69,7 @@ var repoCloneCmd = &cobra.Command{
 			}
 			repoSlugForDir = repoSlug
 
-			repo, err := apiClient.GetRepository(orgSlug, repoSlug)
+			repo, err := apiClient.GetRepository(cmd.Context(), orgSlug, repoSlug)
 			if err != nil {
 				return err
 			}
@@ -81,17 +80,17 @@ var repoCloneCmd = &cobra.Command{
 			if cloneUseHTTPS {
 				if repo.CloneURL.HTTPS != nil && *repo.CloneURL.HTTPS != "" {
 					cloneURL = *repo.CloneURL.HTTPS
-					fmt.Println("Using HTTPS clone URL.")
+					fmt.Fprintln(os.Stderr, "Using HTTPS clone URL.")
 				} else {
 					return fmt.Errorf("HTTPS clone URL not available for this repository")
 				}
 			} else {
 				if repo.CloneURL.SSH != nil && *repo.CloneURL.SSH != "" {
 					cloneURL = *repo.CloneURL.SSH
-					fmt.Println("Using SSH clone URL.")
+					fmt.Fprintln(os.Stderr, "Using SSH clone URL.")
 				} else if repo.CloneURL.HTTPS != nil && *repo.CloneURL.HTTPS != "" {
 					cloneURL = *repo.CloneURL.HTTPS
-					fmt.Println("SSH URL not available, falling back to HTTPS clone URL.")
+					fmt.Fprintln(os.Stderr, "SSH URL not available, falling back to HTTPS clone URL.")
 				} else {
 					return fmt.Errorf("no suitable clone URL (SSH or HTTPS) available for this repository")
 				}
@@ -110,9 +109,10 @@ var repoCloneCmd = &cobra.Command{
 			}
 		}
 
-		fmt.Printf("Cloning into '%s'...\n", targetDirectory)
+		fmt.Fprintf(os.Stderr, "Cloning into '%s'...\n", targetDirectory)
 		gitArgs := []string{"clone", cloneURL, targetDirectory}
 		gitCmd := exec.Command("git", gitArgs...)
+		gitCmd.Env = gitEnv()
 
 		gitCmd.Stdout = os.Stdout
 		gitCmd.Stderr = os.Stderr
@@ -122,7 +122,7 @@ var repoCloneCmd = &cobra.Command{
 			return fmt.Errorf("git clone failed: %w", err)
 		}
 
-		fmt.Println("\nRepository cloned successfully.")
+		fmt.Fprintln(os.Stderr, "\nRepository cloned successfully.")
 		return nil
 	},
 }
@@ -3,13 +3,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"strings"
 
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -27,7 +27,7 @@ Example: src repo create "My Awesome Project" -d "Description here" --slug my-aw
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repoName := args[0]
 
-		orgSlug := viper.GetString("organization")
+		orgSlug := defaultOrganization()
 		if orgSlug == "" {
 			return fmt.Errorf("error: 'organization' slug not found in config.yaml or SOURCECRAFT_ORGANIZATION env var")
 		}
@@ -35,7 +35,7 @@ Example: src repo create "My Awesome Project" -d "Description here" --slug my-aw
 		repoSlug := createSlugFlag
 		if repoSlug == "" {
 			repoSlug = generateSlug(repoName)
-			fmt.Printf("Generated slug: %s (use --slug to override)\n", repoSlug)
+			fmt.Fprintf(os.Stderr, "Generated slug: %s (use --slug to override)\n", repoSlug)
 		}
 
 		visibility := createVisibilityFlag
@@ -43,13 +43,17 @@ Example: src repo create "My Awesome Project" -d "Description here" --slug my-aw
 			return fmt.Errorf("invalid value for --visibility: '%s'. Allowed: public, internal, private", visibility)
 		}
 
-		fmt.Printf("Creating repository '%s/%s' in organization '%s'...\n", orgSlug, repoSlug, orgSlug)
+		fmt.Fprintf(os.Stderr, "Creating repository '%s/%s' in organization '%s'...\n", orgSlug, repoSlug, orgSlug)
 
-		repo, err := apiClient.CreateRepository(orgSlug, repoName, repoSlug, createDescriptionFlag, visibility)
+		repo, err := apiClient.CreateRepository(cmd.Context(), orgSlug, repoName, repoSlug, createDescriptionFlag, visibility)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(repo)
+		}
+
 		createdName := cliutils.DerefString(repo.Name)
 		createdSlug := cliutils.DerefString(repo.Slug)
 		sshUrl := ""
@@ -85,6 +89,7 @@ func generateSlug(name string) string {
 
 func init() {
 	repoCmd.AddCommand(repoCreateCmd)
+	supportsStructuredOutput(repoCreateCmd)
 	repoCreateCmd.Flags().StringVarP(&createDescriptionFlag, "description", "d", "", "Repository description")
 	repoCreateCmd.Flags().StringVar(&createSlugFlag, "slug", "", "Repository slug (URL-friendly name, required by API, auto-generated if omitted)")
 	repoCreateCmd.Flags().StringVar(&createVisibilityFlag, "visibility", "", "Repository visibility: public, internal, private (defaults to organization/server setting)")
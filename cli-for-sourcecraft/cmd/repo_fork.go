@@ -3,12 +3,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -38,13 +38,13 @@ Example: src repo fork source-org/original-repo --name my-copy-slug`,
 
 		targetOrgSlug := forkTargetOrgFlag
 		if targetOrgSlug == "" {
-			targetOrgSlug = viper.GetString("organization")
+			targetOrgSlug = defaultOrganization()
 			if targetOrgSlug == "" {
 				return fmt.Errorf("error: target organization not specified. Use --org flag or set 'organization' in config.yaml")
 			}
-			fmt.Printf("Forking into your organization '%s' (from config)...\n", targetOrgSlug)
+			fmt.Fprintf(os.Stderr, "Forking into your organization '%s' (from config)...\n", targetOrgSlug)
 		} else {
-			fmt.Printf("Forking into specified organization '%s'...\n", targetOrgSlug)
+			fmt.Fprintf(os.Stderr, "Forking into specified organization '%s'...\n", targetOrgSlug)
 		}
 
 		newRepoSlug := forkRepoNameFlag
@@ -54,16 +54,20 @@ Example: src repo fork source-org/original-repo --name my-copy-slug`,
 			targetRepoName = newRepoSlug
 		}
 
-		fmt.Printf("Forking '%s/%s' to '%s/%s'...\n", sourceOrgSlug, sourceRepoSlug, targetOrgSlug, targetRepoName)
+		fmt.Fprintf(os.Stderr, "Forking '%s/%s' to '%s/%s'...\n", sourceOrgSlug, sourceRepoSlug, targetOrgSlug, targetRepoName)
 		if forkDefaultBranchOnlyFlag {
-			fmt.Println("Only copying the default branch.")
+			fmt.Fprintln(os.Stderr, "Only copying the default branch.")
 		}
 
-		forkedRepo, err := apiClient.ForkRepository(sourceOrgSlug, sourceRepoSlug, targetOrgSlug, newRepoSlug, forkDefaultBranchOnlyFlag)
+		forkedRepo, err := apiClient.ForkRepository(cmd.Context(), sourceOrgSlug, sourceRepoSlug, targetOrgSlug, newRepoSlug, forkDefaultBranchOnlyFlag)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(forkedRepo)
+		}
+
 		createdName := cliutils.DerefString(forkedRepo.Name)
 		createdSlug := cliutils.DerefString(forkedRepo.Slug)
 		sshUrl := ""
@@ -86,6 +90,7 @@ Example: src repo fork source-org/original-repo --name my-copy-slug`,
 
 func init() {
 	repoCmd.AddCommand(repoForkCmd)
+	supportsStructuredOutput(repoForkCmd)
 	repoForkCmd.Flags().StringVar(&forkTargetOrgFlag, "org", "", "Organization slug to fork into (defaults to organization in config.yaml)")
 	repoForkCmd.Flags().StringVar(&forkRepoNameFlag, "name", "", "Slug for the new forked repository (defaults to the original repository slug)") // Clarified help text
 	repoForkCmd.Flags().BoolVar(&forkDefaultBranchOnlyFlag, "default-branch-only", false, "Only copy the default branch")
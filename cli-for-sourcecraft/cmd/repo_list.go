@@ -9,7 +9,11 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+)
+
+var (
+	repoListLimitFlag int
+	repoListAllFlag   bool
 )
 
 var repoListCmd = &cobra.Command{
@@ -18,21 +22,29 @@ var repoListCmd = &cobra.Command{
 	Long:  `Shows a list of repositories owned by the organization specified in config.yaml.`,
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := listOptions(repoListLimitFlag, repoListAllFlag)
+		if err != nil {
+			return err
+		}
 
-		orgSlug := viper.GetString("organization")
+		orgSlug := defaultOrganization()
 		if orgSlug == "" {
 			return fmt.Errorf("error: 'organization' not found in config.yaml.")
 		}
 
-		fmt.Printf("Request repositories for your organization '%s'...\n", orgSlug)
+		fmt.Fprintf(os.Stderr, "Request repositories for your organization '%s'...\n", orgSlug)
 
-		repos, err := apiClient.ListRepositories(orgSlug)
+		repos, err := apiClient.ListRepositories(cmd.Context(), orgSlug, opts)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(repos)
+		}
+
 		if len(repos) == 0 {
-			fmt.Println("No repositories found.")
+			fmt.Fprintln(os.Stderr, "No repositories found.")
 			return nil
 		}
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -60,4 +72,6 @@ var repoListCmd = &cobra.Command{
 
 func init() {
 	repoCmd.AddCommand(repoListCmd)
+	supportsStructuredOutput(repoListCmd)
+	addListFlags(repoListCmd, &repoListLimitFlag, &repoListAllFlag)
 }
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -32,82 +33,82 @@ This command must be run from within the root directory of your cloned fork.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
-		fmt.Println("Checking current repository...")
+		fmt.Fprintln(os.Stderr, "Checking current repository...")
 		currentOrg, currentRepo, err := git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 		if err != nil {
-			fmt.Printf("Warning: could not determine current repository from 'origin' remote: %v. Will assume default branch is 'main'.\n", err)
+			fmt.Fprintf(os.Stderr, "Warning: could not determine current repository from 'origin' remote: %v. Will assume default branch is 'main'.\n", err)
 			currentOrg = ""
 			currentRepo = ""
 		} else {
-			fmt.Printf("Detected current repository: %s/%s\n", currentOrg, currentRepo)
+			fmt.Fprintf(os.Stderr, "Detected current repository: %s/%s\n", currentOrg, currentRepo)
 		}
 
 		defaultBranch := "main"
 		if currentOrg != "" && currentRepo != "" {
-			fmt.Println("Fetching repository details to determine default branch...")
-			repoInfo, err := apiClient.GetRepository(currentOrg, currentRepo)
+			fmt.Fprintln(os.Stderr, "Fetching repository details to determine default branch...")
+			repoInfo, err := apiClient.GetRepository(cmd.Context(), currentOrg, currentRepo)
 			if err != nil {
-				fmt.Printf("Warning: failed to get repository info for %s/%s: %v. Assuming default branch is 'main'.\n", currentOrg, currentRepo, err)
+				fmt.Fprintf(os.Stderr, "Warning: failed to get repository info for %s/%s: %v. Assuming default branch is 'main'.\n", currentOrg, currentRepo, err)
 			} else if repoInfo != nil && repoInfo.DefaultBranch != nil && *repoInfo.DefaultBranch != "" {
 				defaultBranch = *repoInfo.DefaultBranch
 			}
 		}
-		fmt.Printf("Target local branch for merge: '%s'\n", defaultBranch)
+		fmt.Fprintf(os.Stderr, "Target local branch for merge: '%s'\n", defaultBranch)
 
-		desiredUpstreamURL, err := ensureUpstreamRemote(syncHTTPSFlag)
+		desiredUpstreamURL, err := ensureUpstreamRemote(cmd.Context(), syncHTTPSFlag)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("Using upstream URL: %s\n", desiredUpstreamURL)
+		fmt.Fprintf(os.Stderr, "Using upstream URL: %s\n", desiredUpstreamURL)
 
-		fmt.Println("Fetching changes from upstream...")
+		fmt.Fprintln(os.Stderr, "Fetching changes from upstream...")
 		if err := runGitCommand("fetch", "upstream"); err != nil {
 			return fmt.Errorf("failed to fetch from upstream: %w", err)
 		}
 
-		fmt.Printf("Switching to local branch '%s'...\n", defaultBranch)
+		fmt.Fprintf(os.Stderr, "Switching to local branch '%s'...\n", defaultBranch)
 		if err := runGitCommand("checkout", defaultBranch); err != nil {
 			return fmt.Errorf("failed to checkout branch '%s': %w. Make sure you don't have uncommitted changes", defaultBranch, err)
 		}
 
 		upstreamDefaultBranchRef := fmt.Sprintf("upstream/%s", defaultBranch)
-		fmt.Printf("Merging changes from '%s' into '%s'...\n", upstreamDefaultBranchRef, defaultBranch)
+		fmt.Fprintf(os.Stderr, "Merging changes from '%s' into '%s'...\n", upstreamDefaultBranchRef, defaultBranch)
 		err = runGitCommand("merge", "--no-ff", upstreamDefaultBranchRef)
 		if err != nil {
 			conflictOutput, statusErr := exec.Command("git", "status", "--porcelain").Output()
 			isConflict := statusErr == nil && strings.Contains(string(conflictOutput), "UU ")
 
 			if isConflict {
-				fmt.Println("\n---")
-				fmt.Println("Warning: Merge resulted in conflicts!")
-				fmt.Println("Please resolve the conflicts manually:")
-				fmt.Println("  1. Edit the conflicted files (look for '<<<<<<<', '=======', '>>>>>>>').")
-				fmt.Println("  2. Run 'git add <resolved-files>' for each resolved file.")
-				fmt.Println("  3. Run 'git commit' to finalize the merge.")
-				fmt.Println("After resolving, you can optionally push to your origin.")
-				fmt.Println("---")
+				fmt.Fprintln(os.Stderr, "\n---")
+				fmt.Fprintln(os.Stderr, "Warning: Merge resulted in conflicts!")
+				fmt.Fprintln(os.Stderr, "Please resolve the conflicts manually:")
+				fmt.Fprintln(os.Stderr, "  1. Edit the conflicted files (look for '<<<<<<<', '=======', '>>>>>>>').")
+				fmt.Fprintln(os.Stderr, "  2. Run 'git add <resolved-files>' for each resolved file.")
+				fmt.Fprintln(os.Stderr, "  3. Run 'git commit' to finalize the merge.")
+				fmt.Fprintln(os.Stderr, "After resolving, you can optionally push to your origin.")
+				fmt.Fprintln(os.Stderr, "---")
 				return nil
 			} else {
 				return fmt.Errorf("merge from '%s' failed: %w", upstreamDefaultBranchRef, err)
 			}
 		}
-		fmt.Println("Merge successful or already up-to-date.")
+		fmt.Fprintln(os.Stderr, "Merge successful or already up-to-date.")
 
 		if syncPushFlag {
-			fmt.Printf("Pushing updated branch '%s' to origin...\n", defaultBranch)
+			fmt.Fprintf(os.Stderr, "Pushing updated branch '%s' to origin...\n", defaultBranch)
 			if err := runGitCommand("push", "origin", defaultBranch); err != nil {
 				return fmt.Errorf("failed to push to origin: %w", err)
 			}
-			fmt.Println("Push successful.")
+			fmt.Fprintln(os.Stderr, "Push successful.")
 		} else if err == nil {
-			fmt.Printf("\nSync complete locally. Run 'git push origin %s' to update your remote fork on SourceCraft.\n", defaultBranch)
+			fmt.Fprintf(os.Stderr, "\nSync complete locally. Run 'git push origin %s' to update your remote fork on SourceCraft.\n", defaultBranch)
 		}
 
 		return nil
 	},
 }
 
-func ensureUpstreamRemote(useHTTPS bool) (string, error) {
+func ensureUpstreamRemote(ctx context.Context, useHTTPS bool) (string, error) {
 	existingUpstreamURL, err := git.GetRemoteURL("upstream")
 	upstreamNotFound := (err != nil && strings.Contains(err.Error(), "not found"))
 
@@ -121,48 +122,48 @@ func ensureUpstreamRemote(useHTTPS bool) (string, error) {
 	}
 
 	if !upstreamNotFound {
-		fmt.Printf("Existing 'upstream' remote found: %s\n", existingUpstreamURL)
+		fmt.Fprintf(os.Stderr, "Existing 'upstream' remote found: %s\n", existingUpstreamURL)
 		isExistingSSH := strings.HasPrefix(existingUpstreamURL, "git@") || strings.HasPrefix(existingUpstreamURL, "ssh://")
 		needsUpdate := (useHTTPS && isExistingSSH) || (!useHTTPS && !isExistingSSH)
 
 		if needsUpdate {
-			fmt.Printf("Protocol mismatch detected (Existing is %s, requested %s). Updating URL...\n", map[bool]string{true: "SSH", false: "HTTPS"}[isExistingSSH], desiredProto)
+			fmt.Fprintf(os.Stderr, "Protocol mismatch detected (Existing is %s, requested %s). Updating URL...\n", map[bool]string{true: "SSH", false: "HTTPS"}[isExistingSSH], desiredProto)
 			upstreamOrgSlug, upstreamRepoSlug, parseErr := git.ParseOwnerAndRepoFromURL(existingUpstreamURL)
 			if parseErr != nil {
-				fmt.Printf("Warning: Could not parse existing upstream URL to update protocol: %v\n", parseErr)
+				fmt.Fprintf(os.Stderr, "Warning: Could not parse existing upstream URL to update protocol: %v\n", parseErr)
 				upstreamOrgSlug, upstreamRepoSlug, parseErr = promptForUpstreamPath(nil)
 				if parseErr != nil {
 					return "", parseErr
 				}
 			}
-			newUpstreamURL, fetchErr := fetchUpstreamURL(upstreamOrgSlug, upstreamRepoSlug, useHTTPS)
+			newUpstreamURL, fetchErr := fetchUpstreamURL(ctx, upstreamOrgSlug, upstreamRepoSlug, useHTTPS)
 			if fetchErr != nil {
 				return "", fetchErr
 			}
 
-			fmt.Printf("Updating 'upstream' remote URL to %s...\n", newUpstreamURL)
+			fmt.Fprintf(os.Stderr, "Updating 'upstream' remote URL to %s...\n", newUpstreamURL)
 			if err := runGitCommand("remote", "set-url", "upstream", newUpstreamURL); err != nil {
 				return "", fmt.Errorf("failed to update 'upstream' remote URL: %w", err)
 			}
 			return newUpstreamURL, nil
 		} else {
-			fmt.Println("Existing upstream URL protocol matches.")
+			fmt.Fprintln(os.Stderr, "Existing upstream URL protocol matches.")
 			return existingUpstreamURL, nil
 		}
 
 	} else {
-		fmt.Println("'upstream' remote not found.")
+		fmt.Fprintln(os.Stderr, "'upstream' remote not found.")
 		upstreamOrgSlug, upstreamRepoSlug, promptErr := promptForUpstreamPath(nil)
 		if promptErr != nil {
 			return "", promptErr
 		}
 
-		newUpstreamURL, fetchErr := fetchUpstreamURL(upstreamOrgSlug, upstreamRepoSlug, useHTTPS)
+		newUpstreamURL, fetchErr := fetchUpstreamURL(ctx, upstreamOrgSlug, upstreamRepoSlug, useHTTPS)
 		if fetchErr != nil {
 			return "", fetchErr
 		}
 
-		fmt.Printf("Adding 'upstream' remote pointing to %s...\n", newUpstreamURL)
+		fmt.Fprintf(os.Stderr, "Adding 'upstream' remote pointing to %s...\n", newUpstreamURL)
 		if err := runGitCommand("remote", "add", "upstream", newUpstreamURL); err != nil {
 			return "", fmt.Errorf("failed to add 'upstream' remote: %w", err)
 		}
@@ -176,7 +177,7 @@ func promptForUpstreamPath(repoInfo *api.Repo) (orgSlug, repoSlug string, err er
 		parentSlugHint = *repoInfo.Parent.Slug
 	}
 
-	fmt.Printf("Please enter the full path of the original repository you forked from\n(e.g., original-owner/%s): ", parentSlugHint)
+	fmt.Fprintf(os.Stderr, "Please enter the full path of the original repository you forked from\n(e.g., original-owner/%s): ", parentSlugHint)
 	reader := bufio.NewReader(os.Stdin)
 	upstreamPathInput, _ := reader.ReadString('\n')
 	upstreamPathInput = strings.TrimSpace(upstreamPathInput)
@@ -188,9 +189,9 @@ func promptForUpstreamPath(repoInfo *api.Repo) (orgSlug, repoSlug string, err er
 	return parts[0], parts[1], nil
 }
 
-func fetchUpstreamURL(orgSlug, repoSlug string, useHTTPS bool) (string, error) {
-	fmt.Printf("Fetching details for upstream repository %s/%s to get clone URL...\n", orgSlug, repoSlug)
-	upstreamRepoInfo, err := apiClient.GetRepository(orgSlug, repoSlug)
+func fetchUpstreamURL(ctx context.Context, orgSlug, repoSlug string, useHTTPS bool) (string, error) {
+	fmt.Fprintf(os.Stderr, "Fetching details for upstream repository %s/%s to get clone URL...\n", orgSlug, repoSlug)
+	upstreamRepoInfo, err := apiClient.GetRepository(ctx, orgSlug, repoSlug)
 	if err != nil {
 		return "", fmt.Errorf("failed to get upstream repository info (%s/%s) for clone URL: %w", orgSlug, repoSlug, err)
 	}
@@ -202,18 +203,18 @@ func fetchUpstreamURL(orgSlug, repoSlug string, useHTTPS bool) (string, error) {
 	if useHTTPS {
 		if upstreamRepoInfo.CloneURL.HTTPS != nil && *upstreamRepoInfo.CloneURL.HTTPS != "" {
 			url = *upstreamRepoInfo.CloneURL.HTTPS
-			fmt.Println("Using HTTPS URL for upstream.")
+			fmt.Fprintln(os.Stderr, "Using HTTPS URL for upstream.")
 		} else if upstreamRepoInfo.CloneURL.SSH != nil && *upstreamRepoInfo.CloneURL.SSH != "" {
 			url = *upstreamRepoInfo.CloneURL.SSH
-			fmt.Println("HTTPS URL not found for upstream, falling back to SSH.")
+			fmt.Fprintln(os.Stderr, "HTTPS URL not found for upstream, falling back to SSH.")
 		}
 	} else {
 		if upstreamRepoInfo.CloneURL.SSH != nil && *upstreamRepoInfo.CloneURL.SSH != "" {
 			url = *upstreamRepoInfo.CloneURL.SSH
-			fmt.Println("Using SSH URL for upstream.")
+			fmt.Fprintln(os.Stderr, "Using SSH URL for upstream.")
 		} else if upstreamRepoInfo.CloneURL.HTTPS != nil && *upstreamRepoInfo.CloneURL.HTTPS != "" {
 			url = *upstreamRepoInfo.CloneURL.HTTPS
-			fmt.Println("SSH URL not found for upstream, falling back to HTTPS.")
+			fmt.Fprintln(os.Stderr, "SSH URL not found for upstream, falling back to HTTPS.")
 		}
 	}
 
@@ -225,9 +226,10 @@ func fetchUpstreamURL(orgSlug, repoSlug string, useHTTPS bool) (string, error) {
 
 func runGitCommand(args ...string) error {
 	cmd := exec.Command("git", args...)
+	cmd.Env = gitEnv()
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	fmt.Printf("Running: git %s\n", strings.Join(args, " "))
+	fmt.Fprintf(os.Stderr, "Running: git %s\n", strings.Join(args, " "))
 	err := cmd.Run()
 	return err
 }
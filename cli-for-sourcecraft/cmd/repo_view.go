@@ -3,12 +3,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var repoViewCmd = &cobra.Command{
@@ -20,18 +20,22 @@ Example: src repo view my-awesome-repo`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repoSlug := args[0]
 
-		orgSlug := viper.GetString("organization")
+		orgSlug := defaultOrganization()
 		if orgSlug == "" {
 			return fmt.Errorf("error: 'organization' slug not found in config.yaml or SOURCECRAFT_ORGANIZATION env var")
 		}
 
-		fmt.Printf("Fetching details for repository '%s/%s'...\n", orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Fetching details for repository '%s/%s'...\n", orgSlug, repoSlug)
 
-		repo, err := apiClient.GetRepository(orgSlug, repoSlug)
+		repo, err := apiClient.GetRepository(cmd.Context(), orgSlug, repoSlug)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(repo)
+		}
+
 		fmt.Println("--- Repository Details ---")
 		fmt.Printf("Name:        %s\n", cliutils.DerefString(repo.Name))
 		fmt.Printf("Slug:        %s\n", cliutils.DerefString(repo.Slug))
@@ -74,4 +78,5 @@ Example: src repo view my-awesome-repo`,
 
 func init() {
 	repoCmd.AddCommand(repoViewCmd)
+	supportsStructuredOutput(repoViewCmd)
 }
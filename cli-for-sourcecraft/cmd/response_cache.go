@@ -0,0 +1,56 @@
+// cmd/response_cache.go
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cli-for-sourcecraft/internal/api"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+var cacheTTLFlag time.Duration
+
+// cacheFlag is the registered --cache flag, used to tell "--cache 0" from "not given".
+var cacheFlag *pflag.Flag
+
+// httpCacheDir — каталог для кеша ответов API.
+func httpCacheDir() string {
+	return filepath.Join(getCacheDir(), "http")
+}
+
+// cacheTTL returns the --cache value or, if the flag was not given, 'cache.ttl' from config.yaml.
+func cacheTTL() (time.Duration, error) {
+	if (cacheFlag != nil && cacheFlag.Changed) || !viper.IsSet("cache.ttl") {
+		if cacheTTLFlag < 0 {
+			return 0, fmt.Errorf("invalid value for --cache: %v. Must not be negative", cacheTTLFlag)
+		}
+		return cacheTTLFlag, nil
+	}
+	ttl, err := time.ParseDuration(strings.TrimSpace(viper.GetString("cache.ttl")))
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid 'cache.ttl' in config: %q. Use a duration like 30s or 5m", viper.GetString("cache.ttl"))
+	}
+	return ttl, nil
+}
+
+// setupCache wraps the client's transport with api.CachingTransport when a cache TTL is set.
+func setupCache(client *api.Client) error {
+	ttl, err := cacheTTL()
+	if err != nil {
+		return err
+	}
+	if ttl == 0 {
+		return nil
+	}
+	client.HTTPClient.Transport = &api.CachingTransport{
+		Base: client.HTTPClient.Transport,
+		Dir:  httpCacheDir(),
+		TTL:  ttl,
+	}
+	return nil
+}
@@ -2,88 +2,202 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"cli-for-sourcecraft/internal/api"
 
-	"github.com/zalando/go-keyring"
-
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var apiClient *api.Client
 var cfgFile string
+var timeoutFlag time.Duration
+
+// cancelTimeout releases the --timeout context once the command has finished.
+var cancelTimeout context.CancelFunc = func() {}
 
 var rootCmd = &cobra.Command{
 	Use:   "src",
 	Short: "CLI для SourceCraft.dev",
 	Long: `A command-line tool (CLI) for the SourceCraft.dev platform.
-Provides access to repositories, pull requests, tasks, and more.`,
+Provides access to repositories, pull requests, tasks, and more.
+
+Exit codes:
+  0    success
+  1    general error
+  3    authentication failed (401/403)
+  4    not found (404)
+  5    rate limited (429)
+  6    network error (no response from the API)
+  7    server error (5xx)
+  124  timed out (--timeout)
+  130  interrupted (Ctrl-C)`,
 
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if timeoutFlag > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeoutFlag)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
 
 		if cmd.Name() == "help" {
 			return nil
 		}
-		if cmd.Parent() != nil && cmd.Parent().Name() == "auth" {
-			return nil
+		if err := checkOutputFlags(cmd); err != nil {
+			return err
 		}
-
-		var token string
-		var err error
-
-		token = os.Getenv("SOURCECRAFT_TOKEN")
-		if token != "" {
-			apiClient = api.NewClient("https://api.sourcecraft.tech", token)
+		if cmd.Parent() != nil && (cmd.Parent().Name() == "auth" || cmd.Parent().Name() == "config" || cmd.Parent().Name() == "cache" || cmd.Parent().Name() == "alias" || cmd.Parent().Name() == "extension") {
 			return nil
 		}
 
-		token, err = keyring.Get(keyringServiceName, keyringTokenUser)
-		if err != nil && err != keyring.ErrNotFound {
-			return fmt.Errorf("error reading OS keyring: %w", err)
+		profile, err := currentHost()
+		if err != nil {
+			return err
 		}
 
-		if err == keyring.ErrNotFound {
-			yamlToken := viper.GetString("token")
+		token, source, err := lookupToken(profile)
+		if err != nil {
+			return err
+		}
 
-			if yamlToken != "" {
-				fmt.Fprintln(os.Stderr, "Token detected in config.yaml, migration to secure storage (OS keyring)...")
+		if token == "" {
+			return fmt.Errorf("error: Token not found for host '%s'.\n"+
+				"Please run the command 'src auth login'.\n"+
+				"Or set the environment variable %s (for CI/CD).", profile.Hostname, hostTokenEnvVar(profile.Hostname))
+		}
 
-				errSet := keyring.Set(keyringServiceName, keyringTokenUser, yamlToken)
-				if errSet != nil {
-					return fmt.Errorf("failed to migrate token to keyring: %w", errSet)
-				}
+		apiClient, err = newAPIClient(profile, token)
+		if err != nil {
+			return err
+		}
+		if source == tokenSourceStore {
+			setupTokenRefresh(apiClient, profile)
+		}
+		if source == tokenSourceLegacyKeyring || source == tokenSourceConfig {
+			migrateLegacyToken(cmd.Context(), apiClient, profile, source)
+		}
+		return nil
+	},
+}
 
-				viper.Set("token", nil)
-				if errWrite := viper.WriteConfig(); errWrite != nil {
-					fmt.Fprintf(os.Stderr, "Warning: Failed to remove token from config.yaml after migration: %v\n", errWrite)
-				}
+// newAPIClient creates the API client for profile, applying the network
+// settings of the profile, the 'retry' settings from config.yaml, the debug
+// tracing flags and --cache.
+func newAPIClient(profile *hostProfile, token string) (*api.Client, error) {
+	client := api.NewClient(profile.APIBaseURL, token)
+	if err := setupTransport(client, profile); err != nil {
+		return nil, err
+	}
+	policy, err := retryPolicyFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	client.Retry = policy
+	if err := setupTracing(client); err != nil {
+		return nil, err
+	}
+	if err := setupCache(client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
 
-				token = yamlToken
-				fmt.Fprintln(os.Stderr, "The migration is complete. The token is now in OS keyring.")
-			}
+// retryPolicyFromConfig reads the retry policy from config.yaml:
+//
+//	retry:
+//	  max_attempts: 5       # total attempts, 1 disables retries
+//	  initial_backoff: 500ms
+//	  max_backoff: 1m
+//
+// Missing keys keep the defaults from api.DefaultRetryPolicy.
+func retryPolicyFromConfig() (api.RetryPolicy, error) {
+	policy := api.DefaultRetryPolicy
+
+	if viper.IsSet("retry.max_attempts") {
+		attempts, err := strconv.Atoi(strings.TrimSpace(viper.GetString("retry.max_attempts")))
+		if err != nil || attempts < 1 {
+			return policy, fmt.Errorf("invalid 'retry.max_attempts' in config: %q. Must be an integer >= 1", viper.GetString("retry.max_attempts"))
 		}
+		policy.MaxAttempts = attempts
+	}
 
-		if token == "" {
-			return fmt.Errorf("error: Token not found.\n" +
-				"Please run the command 'src auth login'.\n" +
-				"Or set the environment variable SOURCECRAFT_TOKEN (for CI/CD).")
+	durations := []struct {
+		key    string
+		target *time.Duration
+	}{
+		{"retry.initial_backoff", &policy.InitialBackoff},
+		{"retry.max_backoff", &policy.MaxBackoff},
+	}
+	for _, d := range durations {
+		if !viper.IsSet(d.key) {
+			continue
 		}
+		value, err := time.ParseDuration(strings.TrimSpace(viper.GetString(d.key)))
+		if err != nil || value <= 0 {
+			return policy, fmt.Errorf("invalid '%s' in config: %q. Use a duration like 500ms, 2s or 1m", d.key, viper.GetString(d.key))
+		}
+		*d.target = value
+	}
 
-		apiClient = api.NewClient("https://api.sourcecraft.tech", token)
-
-		return nil
-	},
+	if policy.MaxBackoff < policy.InitialBackoff {
+		return policy, fmt.Errorf("'retry.max_backoff' (%v) must not be less than 'retry.initial_backoff' (%v)", policy.MaxBackoff, policy.InitialBackoff)
+	}
+	return policy, nil
 }
 
 func Execute() {
 	cobra.OnInitialize(initConfig)
-	if err := rootCmd.Execute(); err != nil {
+
+	// Ctrl-C отменяет контекст: прерываются текущие запросы и паузы между повторами.
+	// Повторный Ctrl-C завершает процесс стандартным образом.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Встроенные команды, затем алиасы, затем расширения src-<name>.
+	// Глобальные флаги перед именем команды (src -v <alias>) не мешают поиску.
+	globals, rest := splitGlobalFlags(os.Args[1:])
+	args, external, err := expandAlias(loadAliases(os.Args[1:]), rest)
+	if err == nil && external == nil {
+		external, err = extensionCommand(globals, args)
+	}
+	if err == nil && external != nil {
+		// Shell-алиас или расширение сами сообщают об ошибках, передаем только код завершения
+		err = external.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			stop()
+			os.Exit(exitErr.ExitCode())
+		}
+	} else if err == nil {
+		args = append(globals, args...)
+		if err = checkJSONFieldsArg(args); err == nil {
+			rootCmd.SetArgs(args)
+			err = rootCmd.ExecuteContext(ctx)
+		}
+	}
+	cancelTimeout()
+	stop()
+	if traceErr := closeTraceFile(); traceErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", traceErr)
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && timeoutFlag > 0 {
+			err = fmt.Errorf("command timed out after %v (--timeout): %w", timeoutFlag, err)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
@@ -91,22 +205,21 @@ func initConfig() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
-		viper.AddConfigPath(".")
-
-		home, err := os.UserHomeDir()
-		if err == nil {
-			configPath := filepath.Join(home, ".config", "src")
-			viper.AddConfigPath(configPath)
-		}
-
+		// Только глобальный файл: настройки репозитория лежат в .sourcecraft/src.yaml
+		viper.AddConfigPath(getConfigDir())
 		viper.SetConfigName("config")
 		viper.SetConfigType("yaml")
 	}
 
+	// Переменные окружения: ключ в верхнем регистре, '.' заменяется на '_' (CACHE_TTL)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, " ", viper.ConfigFileUsed())
+		if verboseFlag {
+			fmt.Fprintln(os.Stderr, "Using configuration file:", viper.ConfigFileUsed())
+		}
+		warnUnknownConfigKeys()
 	} else {
 
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -115,17 +228,19 @@ func initConfig() {
 
 	}
 
-}
-
-func getConfigDir() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "$HOME/.config/src"
+	loadRepoConfig()
+	if verboseFlag && repoConfigPath != "" {
+		fmt.Fprintln(os.Stderr, "Using repository configuration file:", repoConfigPath)
 	}
-	return filepath.Join(home, ".config", "src")
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (example: C:\\Users\\User\\.config\\src\\config.yaml)")
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Detailed log output")
+	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Detailed log output: print HTTP requests and responses to stderr (also: SRC_DEBUG=api, SRC_DEBUG=api,body)")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTLFlag, "cache", 0, "Reuse cached GET responses younger than this (e.g. 5m) and revalidate older ones with ETag (default: 'cache.ttl' from config, 0 = off)")
+	cacheFlag = rootCmd.PersistentFlags().Lookup("cache")
+	rootCmd.PersistentFlags().StringVar(&traceFileFlag, "trace-file", "", "Write an HTTP trace to this file: HAR if the name ends in .har, JSON lines otherwise")
+	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 0, "Abort the command if it takes longer than this (e.g. 30s, 5m; 0 = no limit)")
+	rootCmd.PersistentFlags().StringVar(&asUserFlag, "as", "", "Run the command as another logged in account of the host (see 'src auth switch')")
+	rootCmd.PersistentFlags().StringVar(&hostnameFlag, "hostname", "", "SourceCraft host profile to use (default: $SOURCECRAFT_HOST, 'default_host' or sourcecraft.dev)")
 }
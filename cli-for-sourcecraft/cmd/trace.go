@@ -0,0 +1,88 @@
+// cmd/trace.go
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+// debugEnvVar enables HTTP tracing without --verbose, e.g. SRC_DEBUG=api or SRC_DEBUG=api,body.
+const debugEnvVar = "SRC_DEBUG"
+
+var (
+	verboseFlag   bool
+	traceFileFlag string
+)
+
+// traceRecorder is the open --trace-file, closed by Execute after the command.
+var traceRecorder api.TraceRecorder
+
+// debugOptions reports whether HTTP requests should be logged to stderr and
+// whether bodies should be included. --verbose logs requests; SRC_DEBUG takes
+// a comma-separated list: "api" logs requests, "body" adds bodies.
+func debugOptions() (logAPI, logBodies bool) {
+	logAPI = verboseFlag
+	for _, item := range strings.Split(os.Getenv(debugEnvVar), ",") {
+		switch strings.ToLower(strings.TrimSpace(item)) {
+		case "api", "1", "true", "all":
+			logAPI = true
+		case "body", "bodies":
+			logAPI = true
+			logBodies = true
+		}
+	}
+	return logAPI, logBodies
+}
+
+// setupTracing wraps the client's transport with api.TracingTransport if
+// --verbose, SRC_DEBUG or --trace-file ask for it.
+func setupTracing(client *api.Client) error {
+	tr, err := tracingTransport(client.HTTPClient.Transport)
+	if err != nil {
+		return err
+	}
+	client.HTTPClient.Transport = tr
+	return nil
+}
+
+// tracingTransport wraps base in an api.TracingTransport if --verbose,
+// SRC_DEBUG or --trace-file ask for it, and returns base unchanged otherwise.
+func tracingTransport(base http.RoundTripper) (http.RoundTripper, error) {
+	logAPI, logBodies := debugOptions()
+	if !logAPI && traceFileFlag == "" {
+		return base, nil
+	}
+
+	tracer := &api.TracingTransport{
+		Base:      base,
+		LogBodies: logBodies,
+	}
+	if logAPI {
+		tracer.Log = os.Stderr
+	}
+	if traceFileFlag != "" {
+		// Несколько клиентов за одну команду (например, 'auth status') пишут в один файл
+		if traceRecorder == nil {
+			recorder, err := api.OpenTraceFile(traceFileFlag)
+			if err != nil {
+				return nil, err
+			}
+			traceRecorder = recorder
+		}
+		tracer.Recorder = traceRecorder
+	}
+	return tracer, nil
+}
+
+// closeTraceFile flushes and closes --trace-file, if one was opened.
+func closeTraceFile() error {
+	if traceRecorder == nil {
+		return nil
+	}
+	err := traceRecorder.Close()
+	traceRecorder = nil
+	return err
+}
@@ -45,9 +45,9 @@ Use the --output flag to specify the path to the file.`,
 			outputFile = fmt.Sprintf("%s-%s-%s-%s.artifact", runSlug, workflowSlug, taskSlug, cubeSlug)
 		}
 
-		fmt.Printf("Querying an artifact for %s/%s | Run: %s...\n", orgSlug, repoSlug, runSlug)
+		fmt.Fprintf(os.Stderr, "Querying an artifact for %s/%s | Run: %s...\n", orgSlug, repoSlug, runSlug)
 
-		data, err := apiClient.GetArtifacts(orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
+		data, err := apiClient.GetArtifacts(cmd.Context(), orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
 		if err != nil {
 			return err
 		}
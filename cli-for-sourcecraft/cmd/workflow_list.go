@@ -11,10 +11,11 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var wfListRepoFlag string
+var wfListLimitFlag int
+var wfListAllFlag bool
 
 var workflowListCmd = &cobra.Command{
 	Use:   "list [flags]",
@@ -22,8 +23,11 @@ var workflowListCmd = &cobra.Command{
 	Long:  `Shows a list of all CI/CD runs for the specified repository.`,
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := listOptions(wfListLimitFlag, wfListAllFlag)
+		if err != nil {
+			return err
+		}
 		var orgSlug, repoSlug string
-		var err error
 
 		if wfListRepoFlag != "" {
 			parts := strings.SplitN(wfListRepoFlag, "/", 2)
@@ -35,22 +39,26 @@ var workflowListCmd = &cobra.Command{
 		} else {
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not identify repository from git remote and 'organization' not specified in the config. Use the --repo <org>/<repo>")
 				}
 				return fmt.Errorf("failed to identify repository slug from git remote. Use the --repo <org>/<repo>")
 			}
 		}
-		fmt.Printf("Request CI/CD runs for: %s/%s\n", orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Request CI/CD runs for: %s/%s\n", orgSlug, repoSlug)
 
-		runs, err := apiClient.ListRuns(orgSlug, repoSlug)
+		runs, err := apiClient.ListRuns(cmd.Context(), orgSlug, repoSlug, opts)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(runs)
+		}
+
 		if len(runs) == 0 {
-			fmt.Println("No CI/CD runs found.")
+			fmt.Fprintln(os.Stderr, "No CI/CD runs found.")
 			return nil
 		}
 
@@ -81,5 +89,7 @@ var workflowListCmd = &cobra.Command{
 
 func init() {
 	workflowCmd.AddCommand(workflowListCmd)
+	supportsStructuredOutput(workflowListCmd)
+	addListFlags(workflowListCmd, &wfListLimitFlag, &wfListAllFlag)
 	workflowListCmd.Flags().StringVarP(&wfListRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo>")
 }
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/git"
@@ -40,10 +41,10 @@ ou need to know all 4 slugs. You can find them in the output of 'src workflow st
 			}
 		}
 
-		fmt.Printf("Log request for %s/%s | Run: %s, WF: %s, Task: %s, Cube: %s\n",
+		fmt.Fprintf(os.Stderr, "Log request for %s/%s | Run: %s, WF: %s, Task: %s, Cube: %s\n",
 			orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
 
-		logs, err := apiClient.GetLogs(orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
+		logs, err := apiClient.GetLogs(cmd.Context(), orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
 		if err != nil {
 			return err
 		}
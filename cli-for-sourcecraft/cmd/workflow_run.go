@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/api"
@@ -10,7 +11,6 @@ import (
 	cliutils "cli-for-sourcecraft/internal/utils"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -20,20 +20,26 @@ var (
 )
 
 var workflowRunCmd = &cobra.Command{
-	Use:   "run <workflow_name> [flags]",
+	Use:   "run [<workflow_name>] [flags]",
 	Short: "Run workflow by name",
 	Long: `"Triggers a CI/CD workflow by its name (e.g., 'main' or 'build')."
 
 "<workflow_name> is the name defined in the CI configuration, not the ID."
+"If it is omitted, the 'workflow.default' key is used (e.g. from .sourcecraft/src.yaml)."
 
 "Flags:"
 
 --revision (-r): The branch, tag, or SHA on which to run the workflow (default: the repository's default branch).
 --workflow-revision: The branch, tag, or SHA from which to fetch the workflow YML file (default: the same as --revision).
 `,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		workflowName := args[0]
+		var workflowName string
+		if len(args) > 0 {
+			workflowName = args[0]
+		} else if workflowName = repoSettingString("workflow.default"); workflowName == "" {
+			return fmt.Errorf("no workflow name given and 'workflow.default' is not set. Use 'src workflow run <workflow_name>'")
+		}
 		var orgSlug, repoSlug string
 		var err error
 
@@ -45,10 +51,10 @@ var workflowRunCmd = &cobra.Command{
 			orgSlug = parts[0]
 			repoSlug = parts[1]
 		} else {
-			fmt.Println("Defining a repository from git remote 'origin'...")
+			fmt.Fprintln(os.Stderr, "Defining a repository from git remote 'origin'...")
 			orgSlug, repoSlug, err = git.GetCurrentRepoOwnerAndNameFromRemote("origin")
 			if err != nil {
-				orgSlug = viper.GetString("organization")
+				orgSlug = defaultOrganization()
 				if orgSlug == "" {
 					return fmt.Errorf("could not identify the repository from git remote and 'organization' is not set in the config. Use the -- repo flag")
 				}
@@ -62,17 +68,21 @@ var workflowRunCmd = &cobra.Command{
 			WorkflowRevision: wfRunWorkflowRevisionFlag,
 		}
 
-		fmt.Printf("Run workflow '%s' в %s/%s", workflowName, orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Run workflow '%s' в %s/%s", workflowName, orgSlug, repoSlug)
 		if wfRunRevisionFlag != "" {
-			fmt.Printf(" (on the branch/revision: %s)", wfRunRevisionFlag)
+			fmt.Fprintf(os.Stderr, " (on the branch/revision: %s)", wfRunRevisionFlag)
 		}
-		fmt.Println("...")
+		fmt.Fprintln(os.Stderr, "...")
 
-		runResponse, err := apiClient.RunWorkflow(orgSlug, repoSlug, workflowName, apiBody)
+		runResponse, err := apiClient.RunWorkflow(cmd.Context(), orgSlug, repoSlug, workflowName, apiBody)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(runResponse)
+		}
+
 		fmt.Println("\nWorkflow has been successfully launched!")
 		fmt.Printf("Trigger status: %s\n", cliutils.DerefString(runResponse.TriggerStatus))
 		fmt.Printf("Flux ID: %s\n", cliutils.DerefString(runResponse.FluxID))
@@ -83,6 +93,7 @@ var workflowRunCmd = &cobra.Command{
 
 func init() {
 	workflowCmd.AddCommand(workflowRunCmd)
+	supportsStructuredOutput(workflowRunCmd)
 	workflowRunCmd.Flags().StringVarP(&wfRunRepoFlag, "repo", "R", "", "Specify repository in / format (default: current repository)")
 	workflowRunCmd.Flags().StringVarP(&wfRunRevisionFlag, "revision", "r", "", "Branch, tag, or SHA to run (default: default-branch)")
 	workflowRunCmd.Flags().StringVar(&wfRunWorkflowRevisionFlag, "workflow-revision", "", "Branch, tag, or SHA where to get the YML file from (default: same as --revision)")
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"cli-for-sourcecraft/internal/git"
@@ -37,13 +38,17 @@ var workflowStatusCmd = &cobra.Command{
 			}
 		}
 
-		fmt.Printf("Request launch status '%s' в %s/%s\n", runSlug, orgSlug, repoSlug)
+		fmt.Fprintf(os.Stderr, "Request launch status '%s' в %s/%s\n", runSlug, orgSlug, repoSlug)
 
-		run, err := apiClient.GetRunStatus(orgSlug, repoSlug, runSlug)
+		run, err := apiClient.GetRunStatus(cmd.Context(), orgSlug, repoSlug, runSlug)
 		if err != nil {
 			return err
 		}
 
+		if outputOpts.Enabled() {
+			return printStructured(run)
+		}
+
 		fmt.Printf("\n--- CI/CD Run: %s ---\n", cliutils.DerefString(run.Slug))
 		fmt.Printf("General status: %s\n", cliutils.DerefString(run.Status))
 		fmt.Printf("Launched: %s\n", cliutils.DerefString(run.CreatedAt))
@@ -75,5 +80,6 @@ var workflowStatusCmd = &cobra.Command{
 
 func init() {
 	workflowCmd.AddCommand(workflowStatusCmd)
+	supportsStructuredOutput(workflowStatusCmd)
 	workflowStatusCmd.Flags().StringVarP(&wfStatusRepoFlag, "repo", "R", "", "Specify a repository in the format <org>/<repo>")
 }
@@ -0,0 +1,252 @@
+// internal/api/cache.go
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxCacheBody — ответы больше этого размера (например, артефакты) не кешируются.
+const maxCacheBody = 8 * 1024 * 1024
+
+// CacheHeader is set on responses served by CachingTransport:
+// "hit" (served from disk) or "revalidated" (304 from the server).
+const CacheHeader = "X-Src-Cache"
+
+// CachingTransport is an http.RoundTripper that keeps successful GET
+// responses on disk.
+//
+// A cached response younger than TTL is returned without a request. An older
+// one is revalidated with If-None-Match/If-Modified-Since, and a 304 reply is
+// answered from disk. Entries are keyed by host, path with query, Accept
+// header and a fingerprint of the Authorization header, so different
+// tokens never see each other's responses. Any other successful method
+// (POST, PUT, PATCH, DELETE) invalidates the cached entries related to its path.
+type CachingTransport struct {
+	Base http.RoundTripper // Underlying transport (nil = http.DefaultTransport)
+	Dir  string            // Directory for cache entries
+	TTL  time.Duration     // How long an entry is served without revalidation
+}
+
+// cacheEntry is one cached response as stored on disk.
+type cacheEntry struct {
+	Host     string      `json:"host"`
+	Path     string      `json:"path"` // URL path without the query, used for invalidation
+	URL      string      `json:"url"`
+	StoredAt time.Time   `json:"stored_at"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if req.Method != http.MethodGet {
+		resp, err := base.RoundTrip(req)
+		if err == nil && req.Method != http.MethodHead && req.Method != http.MethodOptions && resp.StatusCode < 400 {
+			t.invalidate(req.URL.Host, req.URL.Path)
+		}
+		return resp, err
+	}
+
+	key := cacheKey(req)
+	entry := t.load(key)
+	if entry != nil && time.Since(entry.StoredAt) < t.TTL {
+		return entry.response(req, "hit"), nil
+	}
+
+	// Устаревшую запись перепроверяем условным запросом
+	if entry != nil && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "" {
+		etag := entry.Header.Get("ETag")
+		lastModified := entry.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			req = req.Clone(req.Context())
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			if lastModified != "" {
+				req.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		for _, h := range []string{"ETag", "Last-Modified", "Date"} {
+			if v := resp.Header.Get(h); v != "" {
+				entry.Header.Set(h, v)
+			}
+		}
+		t.store(key, entry)
+		return entry.response(req, "revalidated"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") ||
+		resp.ContentLength > maxCacheBody {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCacheBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCacheBody {
+		// Слишком большой ответ: отдаем как есть, не кешируя
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(key, &cacheEntry{
+		Host:     req.URL.Host,
+		Path:     req.URL.Path,
+		URL:      req.URL.String(),
+		StoredAt: time.Now(),
+		Status:   resp.StatusCode,
+		Header:   resp.Header.Clone(),
+		Body:     body,
+	})
+	return resp, nil
+}
+
+// response builds an *http.Response from a cache entry.
+func (e *cacheEntry) response(req *http.Request, how string) *http.Response {
+	header := e.Header.Clone()
+	header.Set(CacheHeader, how)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a GET request: host, path with query, Accept and the token.
+func cacheKey(req *http.Request) string {
+	token := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%x", req.URL.Host, req.URL.RequestURI(), req.Header.Get("Accept"), token[:8])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (t *CachingTransport) entryPath(key string) string {
+	return filepath.Join(t.Dir, key+".json")
+}
+
+func (t *CachingTransport) load(key string) *cacheEntry {
+	data, err := os.ReadFile(t.entryPath(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Header == nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes an entry atomically. Errors are ignored: the cache is best effort.
+func (t *CachingTransport) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(t.Dir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(t.Dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), t.entryPath(key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// invalidate removes cached entries related to a mutated path: the path itself
+// and everything below its parent (e.g. POST /repos/o/r/issues drops every
+// cached list and issue of /repos/o/r), plus every ancestor of the path
+// (PATCH /repos/o/r/pulls/5 also drops the /repos/o/r/pulls list).
+func (t *CachingTransport) invalidate(host, path string) {
+	path = "/" + strings.Trim(path, "/")
+	parent := path
+	if i := strings.LastIndex(path, "/"); i > 0 {
+		parent = path[:i]
+	}
+
+	files, err := os.ReadDir(t.Dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		entry := t.load(strings.TrimSuffix(f.Name(), ".json"))
+		if entry == nil || entry.Host != host {
+			continue
+		}
+		cached := "/" + strings.Trim(entry.Path, "/")
+		if cached == parent || strings.HasPrefix(cached, parent+"/") || strings.HasPrefix(path, cached+"/") {
+			os.Remove(filepath.Join(t.Dir, f.Name()))
+		}
+	}
+}
+
+// ClearCache deletes all cache entries in dir and returns how many were removed.
+func ClearCache(dir string) (int, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	removed := 0
+	for _, f := range files {
+		if f.IsDir() || !(strings.HasSuffix(f.Name(), ".json") || strings.HasSuffix(f.Name(), ".tmp")) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, f.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		if strings.HasSuffix(f.Name(), ".json") {
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,213 @@
+// internal/api/cache_test.go
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cacheServer answers GETs with a body naming the path and the token, with
+// ETag "v<version>", and 304 if If-None-Match still matches.
+type cacheServer struct {
+	mu       sync.Mutex
+	version  int
+	status   int // Код ответа на GET (0 = 200)
+	requests []string
+}
+
+func (s *cacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("If-None-Match"))
+	if r.Method != http.MethodGet {
+		s.version++
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	etag := `"v` + strconv.Itoa(s.version) + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+	}
+	io.WriteString(w, r.URL.Path+" for "+r.Header.Get("Authorization")+" "+etag)
+}
+
+func (s *cacheServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// cachedGet sends method to path with token through tr and returns the body
+// and the X-Src-Cache header.
+func cachedGet(t *testing.T, tr *CachingTransport, srv *httptest.Server, method, path, token string) (string, string, int) {
+	t.Helper()
+	req, _ := http.NewRequest(method, srv.URL+path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp.Header.Get(CacheHeader), resp.StatusCode
+}
+
+func newCacheTest(t *testing.T, ttl time.Duration) (*cacheServer, *httptest.Server, *CachingTransport) {
+	cs := &cacheServer{version: 1}
+	srv := httptest.NewServer(cs)
+	t.Cleanup(srv.Close)
+	return cs, srv, &CachingTransport{Base: srv.Client().Transport, Dir: t.TempDir(), TTL: ttl}
+}
+
+func TestCacheHitWithinTTL(t *testing.T) {
+	cs, srv, tr := newCacheTest(t, time.Hour)
+	first, how, _ := cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r?x=1", "a")
+	if how != "" {
+		t.Errorf("first GET: %s = %q, want a response from the server", CacheHeader, how)
+	}
+	second, how, _ := cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r?x=1", "a")
+	if how != "hit" || second != first || cs.count() != 1 {
+		t.Errorf("second GET: %q (%s), %d requests; want the cached body without a request", second, how, cs.count())
+	}
+	// Другой query — другая запись
+	cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r?x=2", "a")
+	if cs.count() != 2 {
+		t.Errorf("%d requests, want a request for another query", cs.count())
+	}
+
+	if runtime.GOOS != "windows" {
+		files, _ := os.ReadDir(tr.Dir)
+		for _, f := range files {
+			if info, _ := f.Info(); info.Mode().Perm() != 0600 {
+				t.Errorf("cache entry %s has mode %v, want 0600", f.Name(), info.Mode().Perm())
+			}
+		}
+	}
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	cs, srv, tr := newCacheTest(t, 0)
+	first, _, _ := cachedGet(t, tr, srv, http.MethodGet, "/me", "a")
+
+	body, how, status := cachedGet(t, tr, srv, http.MethodGet, "/me", "a")
+	if how != "revalidated" || body != first || status != http.StatusOK {
+		t.Errorf("stale GET: %d %q (%s), want the cached body after a 304", status, body, how)
+	}
+	if last := cs.requests[len(cs.requests)-1]; last != `GET /me "v1"` {
+		t.Errorf("revalidation request = %q, want If-None-Match \"v1\"", last)
+	}
+
+	// Ресурс изменился: сервер отдает новое тело, оно заменяет запись
+	cs.version = 2
+	body, how, _ = cachedGet(t, tr, srv, http.MethodGet, "/me", "a")
+	if how != "" || !strings.HasSuffix(body, `"v2"`) {
+		t.Errorf("changed resource: %q (%s), want the new body from the server", body, how)
+	}
+}
+
+func TestCacheIsSeparatePerToken(t *testing.T) {
+	cs, srv, tr := newCacheTest(t, time.Hour)
+	cachedGet(t, tr, srv, http.MethodGet, "/me", "alice")
+
+	body, how, _ := cachedGet(t, tr, srv, http.MethodGet, "/me", "bob")
+	if how != "" || strings.Contains(body, "alice") || cs.count() != 2 {
+		t.Errorf("GET with another token: %q (%s), %d requests; want a fresh response for bob", body, how, cs.count())
+	}
+	if last := cs.requests[1]; strings.HasSuffix(last, `"v1"`) {
+		t.Errorf("request for bob = %q, want no If-None-Match from alice's entry", last)
+	}
+	if body, how, _ := cachedGet(t, tr, srv, http.MethodGet, "/me", "alice"); how != "hit" || !strings.Contains(body, "alice") {
+		t.Errorf("alice again: %q (%s), want her own cached entry", body, how)
+	}
+}
+
+func TestCacheInvalidatedByMutations(t *testing.T) {
+	tests := []struct {
+		method  string
+		path    string
+		dropped []string
+		kept    []string
+	}{
+		{http.MethodPatch, "/repos/o/r/pulls/5", []string{"/repos/o/r/pulls/5", "/repos/o/r/pulls"}, []string{"/repos/o/other", "/me"}},
+		{http.MethodPost, "/repos/o/r/issues", []string{"/repos/o/r/issues", "/repos/o/r/pulls/5", "/repos/o/r/pulls"}, []string{"/repos/o/other", "/me"}},
+		{http.MethodDelete, "/repos/o/other", []string{"/repos/o/other", "/repos/o/r/pulls/5"}, []string{"/me"}},
+		{http.MethodHead, "/repos/o/r/pulls/5", nil, []string{"/repos/o/r/pulls/5", "/repos/o/r/pulls"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			_, srv, tr := newCacheTest(t, time.Hour)
+			for _, path := range append(append([]string{}, tt.dropped...), tt.kept...) {
+				cachedGet(t, tr, srv, http.MethodGet, path, "a")
+			}
+			cachedGet(t, tr, srv, tt.method, tt.path, "a")
+			for _, path := range tt.dropped {
+				if _, how, _ := cachedGet(t, tr, srv, http.MethodGet, path, "a"); how == "hit" {
+					t.Errorf("GET %s after %s %s came from the cache", path, tt.method, tt.path)
+				}
+			}
+			for _, path := range tt.kept {
+				if _, how, _ := cachedGet(t, tr, srv, http.MethodGet, path, "a"); how != "hit" {
+					t.Errorf("GET %s after %s %s: %s = %q, want a hit", path, tt.method, tt.path, CacheHeader, how)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheFailedMutationKeepsEntries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	tr := &CachingTransport{Base: srv.Client().Transport, Dir: t.TempDir(), TTL: time.Hour}
+
+	cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r/pulls", "a")
+	cachedGet(t, tr, srv, http.MethodPost, "/repos/o/r/pulls", "a")
+	if _, how, _ := cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r/pulls", "a"); how != "hit" {
+		t.Errorf("after a failed POST: %s = %q, want the entry kept", CacheHeader, how)
+	}
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusForbidden, http.StatusInternalServerError, http.StatusAccepted} {
+		cs, srv, tr := newCacheTest(t, time.Hour)
+		cs.status = status
+		cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r", "a")
+		_, how, got := cachedGet(t, tr, srv, http.MethodGet, "/repos/o/r", "a")
+		if how != "" || got != status || cs.count() != 2 {
+			t.Errorf("status %d: second GET %d (%s) after %d requests, want it sent to the server again", status, got, how, cs.count())
+		}
+	}
+
+	// Cache-Control: no-store тоже не кешируется
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "private, no-store")
+		io.WriteString(w, "secret")
+	}))
+	defer srv.Close()
+	tr := &CachingTransport{Base: srv.Client().Transport, Dir: t.TempDir(), TTL: time.Hour}
+	cachedGet(t, tr, srv, http.MethodGet, "/x", "a")
+	cachedGet(t, tr, srv, http.MethodGet, "/x", "a")
+	if requests != 2 {
+		t.Errorf("no-store response: %d requests, want 2", requests)
+	}
+}
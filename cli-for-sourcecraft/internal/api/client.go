@@ -4,23 +4,29 @@ package api
 import (
 	"bytes"
 	cliutils "cli-for-sourcecraft/internal/utils"
-	"crypto/tls" // For disabling TLS verification
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 )
 
-const maxRetries = 3
-const initialRetryDelay = 1 * time.Second
-
 // Client for the SourceCraft API
 type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	Token      string
+	Retry      RetryPolicy // Retry policy for failed requests (see retry.go)
+
+	// Refresh, if set, is called once per request when the API answers 401,
+	// e.g. because an OAuth access token has expired. It returns a new token;
+	// the request is then repeated with it.
+	Refresh func(ctx context.Context) (string, error)
 }
 
 // User struct (from Swagger definition, potentially incomplete)
@@ -31,20 +37,35 @@ type User struct {
 	// Add other fields if needed from a full User definition if available
 }
 
-// NewClient constructor
+// NewClient constructor.
+// The HTTP client has no overall Timeout: deadlines and cancellation come from
+// the context passed to each method, so large downloads are not cut off.
+// The transport honours HTTPS_PROXY/NO_PROXY; use NewTransport to configure
+// a proxy, CA bundle or client certificate explicitly.
 func NewClient(baseURL string, token string) *Client {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: false},
-	}
+	tr, _ := NewTransport(TransportConfig{}) // Пустая конфигурация не может вернуть ошибку
 	return &Client{
 		BaseURL:    baseURL,
-		HTTPClient: &http.Client{Timeout: 10 * time.Second, Transport: tr},
+		HTTPClient: &http.Client{Transport: tr},
 		Token:      token,
+		Retry:      DefaultRetryPolicy,
+	}
+}
+
+// sleepContext waits for d, returning early with ctx.Err() if ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
-// makeRequest private helper for API calls
-func (c *Client) makeRequest(method, path string, body interface{}) ([]byte, error) {
+// makeRequest private helper for API calls that return JSON
+func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
 	var reqBody []byte
 	var err error
 	if body != nil {
@@ -56,124 +77,166 @@ func (c *Client) makeRequest(method, path string, body interface{}) ([]byte, err
 	if path != "" && !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	fullURL := c.BaseURL + path
 
-	for i := 0; i < maxRetries; i++ {
+	resp, respBody, err := c.send(ctx, method, path, nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
 
-		// 1. Создание запроса
-		// Создаем новый Reader для тела при каждой попытке, так как он будет прочитан
-		req, err := http.NewRequest(method, fullURL, bytes.NewBuffer(reqBody))
-		if err != nil {
-			return nil, fmt.Errorf("request creation error: %w", err)
+	// Успешный ответ (2xx) должен быть JSON
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/json") {
+		snippet := string(respBody)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
 		}
+		return nil, fmt.Errorf(
+			"API returned non-JSON response (Content-Type: %s) despite 2xx status. Path: %s. Body start: %s",
+			contentType, path, snippet,
+		)
+	}
+	return respBody, nil
+}
+
+// send performs a request, retrying it according to c.Retry. header may
+// override the default headers (e.g. Accept) or add an Idempotency-Key.
+// It returns the final response together with its body; the response body
+// itself is already closed. A non-2xx response is also returned as *Error,
+// a failure without a response as *NetworkError (with a nil response).
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, body []byte) (*http.Response, []byte, error) {
+	policy := c.Retry.normalized()
+	idempotent := isIdempotent(method, header)
+	fullURL := c.BaseURL + path
+	refreshed := false
 
+	for attempt := 1; ; attempt++ {
+		// Новый Reader для тела при каждой попытке, так как предыдущий уже прочитан
+		req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, fmt.Errorf("request creation error: %w", err)
+		}
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
+		for key, values := range header {
+			req.Header[http.CanonicalHeaderKey(key)] = values
+		}
 
-		// 2. Выполнение запроса
 		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
-			// Ошибка сети/таймаута: повторяем с экспоненциальной задержкой
-			if i < maxRetries-1 {
-				waitTime := initialRetryDelay * time.Duration(1<<i)
-				fmt.Printf("Warning: Request failed (network/timeout). Retrying in %v (Attempt %d/%d)...\n", waitTime, i+1, maxRetries)
-				time.Sleep(waitTime)
-				continue
+			netErr := &NetworkError{Method: method, Path: path, Err: err}
+			// Запрос отменен (Ctrl-C, --timeout): не повторяем
+			if ctx.Err() != nil {
+				return nil, nil, netErr
 			}
-			return nil, fmt.Errorf("request execution error after %d retries: %w", maxRetries, err)
-		}
-		defer resp.Body.Close()
-
-		// 3. Обработка Rate Limit (429)
-		if resp.StatusCode == http.StatusTooManyRequests { // 429
-			var waitTime time.Duration
-			retryAfterStr := resp.Header.Get("Retry-After")
-
-			if retryAfterStr != "" {
-				// Пытаемся парсить как секунды
-				if seconds, parseErr := time.ParseDuration(retryAfterStr + "s"); parseErr == nil {
-					waitTime = seconds
-				} else {
-					// Если не секунды, используем экспоненциальный backoff
-					waitTime = initialRetryDelay * time.Duration(1<<i)
-				}
-			} else {
-				// Если заголовок отсутствует, используем экспоненциальный backoff
-				waitTime = initialRetryDelay * time.Duration(1<<i)
+			// Неидемпотентный запрос мог дойти до сервера: повтор создаст дубликат
+			if !idempotent {
+				return nil, nil, netErr
 			}
-
-			if i < maxRetries-1 {
-				// Ждем и повторяем
-				fmt.Printf("Warning: Rate limit hit (429). Retrying in %v (Attempt %d/%d)...\n", waitTime, i+1, maxRetries)
-				time.Sleep(waitTime)
-				continue
+			if attempt >= policy.MaxAttempts {
+				return nil, nil, fmt.Errorf("giving up after %d attempts: %w", attempt, netErr)
 			}
-
-			// Если это была последняя попытка
-			return nil, fmt.Errorf("API request failed due to rate limiting (429) after %d retries", maxRetries)
+			wait := policy.backoff(attempt - 1)
+			fmt.Fprintf(os.Stderr, "Warning: %s %s failed (network/timeout). Retrying in %v (Attempt %d/%d)...\n",
+				method, path, wait.Round(time.Millisecond), attempt, policy.MaxAttempts)
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, nil, err
+			}
+			continue
 		}
 
-		// 4. Чтение тела ответа (происходит только один раз при успешном запросе или не-429 ошибке)
+		// Тело закрываем сразу, а не через defer: иначе при повторах
+		// соединения остаются занятыми до выхода из функции
 		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
-			return nil, fmt.Errorf("API response read error: %w", err)
-		}
-
-		// 5. Обработка других ошибок (4xx, 5xx)
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			errMsg := fmt.Sprintf("API returned error: %s (path: %s)", resp.Status, path)
-			if resp.StatusCode == http.StatusUnauthorized {
-				errMsg = fmt.Sprintf("API error: %s (Invalid or expired token, path: %s)", resp.Status, path)
-			} else if resp.StatusCode == http.StatusNotFound {
-				errMsg = fmt.Sprintf("API error: %s (Incorrect path: %s)", resp.Status, path)
-			} else if resp.StatusCode == http.StatusMethodNotAllowed {
-				errMsg = fmt.Sprintf("API error: %s (Wrong HTTP method for path: %s)", resp.Status, path)
-			}
-			if len(respBody) > 0 {
-				snippet := string(respBody)
-				if len(snippet) > 500 {
-					snippet = snippet[:500] + "..."
-				}
-				var jsonError map[string]interface{}
-				if json.Unmarshal(respBody, &jsonError) == nil {
-					if msg, ok := jsonError["message"].(string); ok {
-						if reqID, ok := jsonError["request_id"].(string); ok {
-							errMsg = fmt.Sprintf("%s. Message: %s (Request ID: %s)", errMsg, msg, reqID)
-						} else {
-							errMsg = fmt.Sprintf("%s. Message: %s", errMsg, msg)
-						}
-					}
-				} else {
-					errMsg = fmt.Sprintf("%s. Response Body: %s", errMsg, snippet)
-				}
-			}
-			return nil, fmt.Errorf(errMsg)
+			return nil, nil, &NetworkError{Method: method, Path: path, Err: fmt.Errorf("API response read error: %w", err)}
 		}
 
-		// 6. Успешный ответ (2xx)
-		contentType := resp.Header.Get("Content-Type")
-		if !strings.HasPrefix(contentType, "application/json") {
-			snippet := string(respBody)
-			if len(snippet) > 150 {
-				snippet = snippet[:150] + "..."
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, respBody, nil
+		}
+
+		apiErr := newError(resp, method, path, respBody)
+		// Истекший токен: обновляем один раз и повторяем запрос. Это безопасно
+		// и для POST: на 401 сервер запрос не выполнял
+		if resp.StatusCode == http.StatusUnauthorized && c.Refresh != nil && !refreshed {
+			refreshed = true
+			token, err := c.Refresh(ctx)
+			if err != nil {
+				return resp, respBody, fmt.Errorf("%w. Token refresh failed: %v", apiErr, err)
 			}
-			return nil, fmt.Errorf(
-				"API returned non-JSON response (Content-Type: %s) despite 2xx status. Path: %s. Body start: %s",
-				contentType, path, snippet,
-			)
+			c.Token = token
+			attempt--
+			continue
+		}
+		if !retryableStatus(resp.StatusCode, idempotent) || attempt >= policy.MaxAttempts {
+			return resp, respBody, apiErr
+		}
+		wait := apiErr.RetryAfter
+		if wait == 0 {
+			wait = policy.backoff(attempt - 1)
+		}
+		// Сервер просит ждать дольше, чем мы готовы: отдаем ошибку с подсказкой Retry-After
+		if wait > policy.MaxBackoff {
+			return resp, respBody, apiErr
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s %s returned %s. Retrying in %v (Attempt %d/%d)...\n",
+			method, path, resp.Status, wait.Round(time.Millisecond), attempt, policy.MaxAttempts)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, nil, err
 		}
-		return respBody, nil
 	}
+}
 
-	return nil, fmt.Errorf("internal error: retry loop failed to complete")
+// Do sends a raw request to path (relative to BaseURL, may include a query
+// string) with the client's token and retry policy. It is meant for endpoints
+// that have no dedicated method. header is added to (and may override) the
+// default JSON headers; body is sent as is.
+//
+// The returned response has its body already read into the []byte result.
+// A non-2xx response is returned together with an *Error, so callers can
+// still print the body and headers.
+func (c *Client) Do(ctx context.Context, method, path string, header http.Header, body []byte) (*http.Response, []byte, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.send(ctx, strings.ToUpper(method), path, header, body)
 }
 
 type SetDecisionBody struct {
 	ReviewDecision string `json:"review_decision"` // e.g., "approve", "block"
 }
 
-// MergeParameters (как в структуре PullRequest, для использования в теле запроса, если API их примет)
+// Значения review_decision для POST .../pulls/{slug}/decision (SetDecisionBody).
+// Других значений API не описывает: слияние — это тоже "approve", но вместе
+// с merge_parameters (MergeDecisionBody, см. MergePullRequest).
+const (
+	// DecisionApprove без merge_parameters только одобряет PR.
+	DecisionApprove = "approve"
+	// DecisionBlock блокирует слияние до снятия решения.
+	DecisionBlock = "block"
+)
+
+// PullRequestReviewer — ревьюер PR и его текущее решение (пусто, если решения нет)
+type PullRequestReviewer struct {
+	User     *User   `json:"user"`
+	Decision *string `json:"decision"`
+}
+
+// CreatePullRequestCommentBody — комментарий к PR (итог ревью)
+type CreatePullRequestCommentBody struct {
+	Content string `json:"content"`
+}
+
+type PullRequestComment struct {
+	ID        *string `json:"id"`
+	Author    *User   `json:"author"`
+	Content   *string `json:"content"`
+	CreatedAt *string `json:"created_at"`
+}
+
+// MergeParameters (как в структуре PullRequest): стратегия слияния и удаление исходной ветки
 type MergeParameters struct {
 	Rebase       bool `json:"rebase"`
 	Squash       bool `json:"squash"`
@@ -244,9 +307,20 @@ type UpdateIssueBody struct {
 	// Labels и Linked PRs пока опускаем для простоты update
 }
 
+// UpdatePullRequestBody ('src pr edit'): передаются только изменённые поля.
+// ReviewerIDs заменяет весь список ревьюеров.
+type UpdatePullRequestBody struct {
+	Title        *string   `json:"title,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	TargetBranch *string   `json:"target_branch,omitempty"`
+	ReviewerIDs  *[]string `json:"reviewer_ids,omitempty"`
+	// MergeParameters — стратегия, с которой сервер сольёт PR ('src pr merge --auto')
+	MergeParameters *MergeParameters `json:"merge_parameters,omitempty"`
+}
+
 type MergeDecisionBody struct {
-	ReviewDecision  string          `json:"review_decision"`  // Всегда "approve" для слияния
-	MergeParameters MergeParameters `json:"merge_parameters"` // Отправляем параметры
+	ReviewDecision  string          `json:"review_decision"`  // Всегда DecisionApprove: слияние — это одобрение с параметрами
+	MergeParameters MergeParameters `json:"merge_parameters"` // Стратегия слияния
 }
 
 type SetDecisionResponse struct {
@@ -301,7 +375,7 @@ type CreatePullRequestBody struct {
 	SourceBranch string   `json:"source_branch"`          // Обязательно
 	TargetBranch string   `json:"target_branch"`          // Обязательно
 	Description  string   `json:"description,omitempty"`  // Не обязательно
-	ReviewerIDs  []string `json:"reviewer_ids,omitempty"` // Не обязательно (пока ID)
+	ReviewerIDs  []string `json:"reviewer_ids,omitempty"` // Не обязательно (ID; slug'и переводятся через GetUser)
 	Publish      bool     `json:"publish"`                // true = Опубликовать, false = Черновик
 }
 
@@ -311,10 +385,17 @@ type PullRequest struct {
 	Slug         *string `json:"slug"`
 	Author       *User   `json:"author"`
 	Title        *string `json:"title"`
+	Description  *string `json:"description,omitempty"`
 	SourceBranch *string `json:"source_branch"`
 	TargetBranch *string `json:"target_branch"`
-	Status       *string `json:"status"`
-	UpdatedAt    *string `json:"updated_at"`
+	// SourceRepository — репозиторий исходной ветки (форк); nil или совпадает с целевым для PR внутри репозитория
+	SourceRepository *RepositoryEmbedded `json:"source_repository,omitempty"`
+	Status           *string             `json:"status"`
+	UpdatedAt        *string             `json:"updated_at"`
+	// MergeParameters — параметры, с которыми PR был или будет слит
+	MergeParameters *MergeParameters `json:"merge_parameters,omitempty"`
+	// Reviewers — назначенные ревьюеры и их решения
+	Reviewers []PullRequestReviewer `json:"reviewers,omitempty"`
 	// Добавь сюда другие поля из Swagger, если нужно будет их выводить
 }
 
@@ -426,25 +507,9 @@ type ListRunsResponse struct {
 // --- API Methods ---
 
 // ListRepositories ('src repo list') uses GET /orgs/{org_slug}/repos
-func (c *Client) ListRepositories(orgSlug string) ([]Repo, error) {
+func (c *Client) ListRepositories(ctx context.Context, orgSlug string, opts ListOptions) ([]Repo, error) {
 	path := fmt.Sprintf("/orgs/%s/repos", orgSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-	var response struct {
-		Repositories  []Repo  `json:"repositories"`
-		NextPageToken *string `json:"next_page_token"`
-	}
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		snippet := string(respBody)
-		if len(snippet) > 150 {
-			snippet = snippet[:150] + "..."
-		}
-		return nil, fmt.Errorf("failed to decode repo list JSON from GET %s: %w. Response start: %s", path, err, snippet)
-	}
-	// TODO: Handle pagination using response.NextPageToken
-	return response.Repositories, nil
+	return collect(NewPaginator[Repo](ctx, c, path, "repositories", opts), opts)
 }
 
 type RunCIBody struct {
@@ -461,10 +526,10 @@ type RunCIWorkflowResponse struct {
 }
 
 // CreateRepository ('src repo create <name>') uses POST /orgs/{org_slug}/repos
-func (c *Client) CreateRepository(orgSlug, name, slug, description, visibility string) (*Repo, error) {
+func (c *Client) CreateRepository(ctx context.Context, orgSlug, name, slug, description, visibility string) (*Repo, error) {
 	path := fmt.Sprintf("/orgs/%s/repos", orgSlug)
 	reqBody := createRepoRequest{Name: name, Slug: slug, Description: description, Visibility: visibility}
-	respBody, err := c.makeRequest(http.MethodPost, path, reqBody)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -481,12 +546,12 @@ func (c *Client) CreateRepository(orgSlug, name, slug, description, visibility s
 
 // GetRepository uses GET /repos/{org_slug}/{repo_slug}
 // Includes the 'Parent' field.
-func (c *Client) GetRepository(orgSlug, repoSlug string) (*Repo, error) {
+func (c *Client) GetRepository(ctx context.Context, orgSlug, repoSlug string) (*Repo, error) {
 	path := fmt.Sprintf("/repos/%s/%s", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
-			return nil, fmt.Errorf("repository '%s/%s' not found or you don't have permission", orgSlug, repoSlug)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("repository '%s/%s' not found or you don't have permission: %w", orgSlug, repoSlug, err)
 		}
 		return nil, err
 	}
@@ -512,10 +577,10 @@ type ForkRepositoryBody struct {
 	DefaultBranchOnly bool   `json:"default_branch_only,omitempty"`
 }
 
-func (c *Client) ForkRepository(sourceOrgSlug, sourceRepoSlug, targetOrgSlug, newRepoSlug string, defaultBranchOnly bool) (*Repo, error) {
+func (c *Client) ForkRepository(ctx context.Context, sourceOrgSlug, sourceRepoSlug, targetOrgSlug, newRepoSlug string, defaultBranchOnly bool) (*Repo, error) {
 	path := fmt.Sprintf("/repos/%s/%s/fork", sourceOrgSlug, sourceRepoSlug)
 	reqBody := ForkRepositoryBody{OrgSlug: targetOrgSlug, Slug: newRepoSlug, DefaultBranchOnly: defaultBranchOnly}
-	respBody, err := c.makeRequest(http.MethodPost, path, reqBody)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -532,36 +597,16 @@ func (c *Client) ForkRepository(sourceOrgSlug, sourceRepoSlug, targetOrgSlug, ne
 
 // ListPullRequests fetches pull requests for a specific repository.
 // Uses GET /repos/{org_slug}/{repo_slug}/pulls
-func (c *Client) ListPullRequests(orgSlug, repoSlug string) ([]PullRequest, error) {
+// Response according to Swagger: ListRepositoryPullRequestsResponse
+func (c *Client) ListPullRequests(ctx context.Context, orgSlug, repoSlug string, opts ListOptions) ([]PullRequest, error) {
 	path := fmt.Sprintf("/repos/%s/%s/pulls", orgSlug, repoSlug)
 	// TODO: Add query parameters later for filtering by state (e.g., ?state=open)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil) // Method GET
-	if err != nil {
-		return nil, err
-	}
-
-	// Response according to Swagger: ListRepositoryPullRequestsResponse
-	var response struct {
-		PullRequests  []PullRequest `json:"pull_requests"`
-		NextPageToken *string       `json:"next_page_token"`
-	}
-
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		snippet := string(respBody)
-		if len(snippet) > 150 {
-			snippet = snippet[:150] + "..."
-		}
-		return nil, fmt.Errorf("failed to decode PR list JSON from GET %s: %w. Response start: %s", path, err, snippet)
-	}
-
-	// TODO: Handle pagination using response.NextPageToken
-
-	return response.PullRequests, nil
+	return collect(NewPaginator[PullRequest](ctx, c, path, "pull_requests", opts), opts)
 }
 
-func (c *Client) CreatePullRequest(orgSlug, repoSlug string, body CreatePullRequestBody) (*PullRequest, error) {
+func (c *Client) CreatePullRequest(ctx context.Context, orgSlug, repoSlug string, body CreatePullRequestBody) (*PullRequest, error) {
 	path := fmt.Sprintf("/repos/%s/%s/pulls", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodPost, path, body) // Метод POST
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, body) // Метод POST
 	if err != nil {
 		// Обрабатываем возможные ошибки (422 - неверные ветки, 409 - уже существует)
 		return nil, err
@@ -579,16 +624,16 @@ func (c *Client) CreatePullRequest(orgSlug, repoSlug string, body CreatePullRequ
 	return &newPR, nil
 }
 
-func (c *Client) GetPullRequest(orgSlug, repoSlug, prSlug string) (*PullRequest, error) {
+func (c *Client) GetPullRequest(ctx context.Context, orgSlug, repoSlug, prSlug string) (*PullRequest, error) {
 	//
 	// *** ПУТЬ: /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug} ***
 	//
 	path := fmt.Sprintf("/repos/%s/%s/pulls/%s", orgSlug, repoSlug, prSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil) // Метод GET
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil) // Метод GET
 	if err != nil {
 		// Обрабатываем 404 Not Found
-		if strings.Contains(err.Error(), "404 Not Found") {
-			return nil, fmt.Errorf("pull request '%s/%s#%s' not found or you don't have permission", orgSlug, repoSlug, prSlug)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("pull request '%s/%s#%s' not found or you don't have permission: %w", orgSlug, repoSlug, prSlug, err)
 		}
 		return nil, err
 	}
@@ -605,74 +650,145 @@ func (c *Client) GetPullRequest(orgSlug, repoSlug, prSlug string) (*PullRequest,
 	return &pr, nil
 }
 
-func (c *Client) MergePullRequest(orgSlug, repoSlug, prSlug string, mergeParams MergeParameters) (*SetDecisionResponse, error) {
-	//
-	// *** ПУТЬ ИЗМЕНЕН: .../merge -> .../decision ***
-	//
-	path := fmt.Sprintf("/repos/%s/%s/pulls/%s/decision", orgSlug, repoSlug, prSlug)
+// UpdatePullRequest ('src pr edit <id>', 'src pr merge --auto')
+// (PATCH /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug})
+func (c *Client) UpdatePullRequest(ctx context.Context, orgSlug, repoSlug, prSlug string, body UpdatePullRequestBody) (*PullRequest, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%s", orgSlug, repoSlug, prSlug)
+	respBody, err := c.makeRequest(ctx, http.MethodPatch, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var updatedPR PullRequest
+	if err := json.Unmarshal(respBody, &updatedPR); err != nil {
+		snippet := string(respBody)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return nil, fmt.Errorf("failed to decode updated PR JSON from PATCH %s: %w. Response start: %s", path, err, snippet)
+	}
+	return &updatedPR, nil
+}
 
-	//
-	// *** ТЕЛО ЗАПРОСА ИЗМЕНЕНО: MergeDecisionBody -> SetDecisionBody ***
-	//
-	// Мы жестко задаем "approve", так как это команда "merge".
-	// mergeParams (squash, rebase, delete_branch) ИГНОРИРУЮТСЯ.
-	//
-	reqBody := SetDecisionBody{
-		ReviewDecision: "approve",
+// MergePullRequest ('src pr merge')
+// (POST /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug}/decision)
+// Отдельного эндпоинта слияния нет: PR сливается решением "approve" с
+// merge_parameters (MergeDecisionBody), поэтому оно записывается и как
+// одобрение вызывающего. Сервер отвечает ошибкой, если PR не готов.
+func (c *Client) MergePullRequest(ctx context.Context, orgSlug, repoSlug, prSlug string, mergeParams MergeParameters) (*SetDecisionResponse, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%s/decision", orgSlug, repoSlug, prSlug)
+	reqBody := MergeDecisionBody{
+		ReviewDecision:  DecisionApprove,
+		MergeParameters: mergeParams,
 	}
 
-	// Вызываем makeRequest с POST
-	respBody, err := c.makeRequest(http.MethodPost, path, reqBody)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	//
-	// *** ОТВЕТ ИЗМЕНЕН: PullRequest -> SetDecisionResponse ***
-	//
 	var decisionResponse SetDecisionResponse
 	if err := json.Unmarshal(respBody, &decisionResponse); err != nil {
 		snippet := string(respBody)
 		if len(snippet) > 150 {
 			snippet = snippet[:150] + "..."
 		}
-		return nil, fmt.Errorf("failed to decode merge/decision response JSON: %w. Response start: %s", err, snippet)
+		return nil, fmt.Errorf("failed to decode decision response JSON from POST %s: %w. Response start: %s", path, err, snippet)
 	}
 	return &decisionResponse, nil
 }
 
-// ListRepositoryIssues ('src issue list')
-// (GET /repos/{org_slug}/{repo_slug}/issues)
-func (c *Client) ListRepositoryIssues(orgSlug, repoSlug string) ([]Issue, error) {
-	path := fmt.Sprintf("/repos/%s/%s/issues", orgSlug, repoSlug)
-	// TODO: Добавить query-параметры для фильтрации (state=open, etc.)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
+// SetPullRequestDecision ('src pr review --approve/--request-changes')
+// (POST /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug}/decision)
+// decision — DecisionApprove или DecisionBlock; merge_parameters не передаются,
+// поэтому одобрение само по себе PR не сливает.
+func (c *Client) SetPullRequestDecision(ctx context.Context, orgSlug, repoSlug, prSlug, decision string) (*SetDecisionResponse, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%s/decision", orgSlug, repoSlug, prSlug)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, SetDecisionBody{ReviewDecision: decision})
 	if err != nil {
 		return nil, err
 	}
-
-	// Ответ по Swagger: ListRepositoryIssuesResponse
-	var response struct {
-		Issues        []Issue `json:"issues"`
-		NextPageToken *string `json:"next_page_token"`
+	var decisionResponse SetDecisionResponse
+	if err := json.Unmarshal(respBody, &decisionResponse); err != nil {
+		snippet := string(respBody)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return nil, fmt.Errorf("failed to decode decision response JSON from POST %s: %w. Response start: %s", path, err, snippet)
 	}
+	return &decisionResponse, nil
+}
+
+// ResetPullRequestDecision ('src pr review --reset') снимает решение текущего пользователя.
+// (DELETE /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug}/decision)
+func (c *Client) ResetPullRequestDecision(ctx context.Context, orgSlug, repoSlug, prSlug string) error {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%s/decision", orgSlug, repoSlug, prSlug)
+	// Ответ может быть пустым (204), поэтому makeRequest с проверкой JSON не подходит
+	_, _, err := c.send(ctx, http.MethodDelete, path, nil, nil)
+	return err
+}
 
-	if err := json.Unmarshal(respBody, &response); err != nil {
+// CreatePullRequestComment ('src pr review --comment' и итог ревью)
+// (POST /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug}/comments)
+func (c *Client) CreatePullRequestComment(ctx context.Context, orgSlug, repoSlug, prSlug, content string) (*PullRequestComment, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%s/comments", orgSlug, repoSlug, prSlug)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, CreatePullRequestCommentBody{Content: content})
+	if err != nil {
+		return nil, err
+	}
+	var comment PullRequestComment
+	if err := json.Unmarshal(respBody, &comment); err != nil {
 		snippet := string(respBody)
 		if len(snippet) > 150 {
 			snippet = snippet[:150] + "..."
 		}
-		return nil, fmt.Errorf("failed to decode issue list JSON from GET %s: %w. Response start: %s", path, err, snippet)
+		return nil, fmt.Errorf("failed to decode comment JSON from POST %s: %w. Response start: %s", path, err, snippet)
 	}
-	// TODO: Handle pagination
-	return response.Issues, nil
+	return &comment, nil
+}
+
+// GetPullRequestDiff ('src pr diff') возвращает изменения PR в формате unified diff.
+// (GET /repos/{org_slug}/{repo_slug}/pulls/{pull_request_slug}/diff)
+// Ошибка с ErrNotFound означает, что сервер не отдаёт diff: используйте git.
+func (c *Client) GetPullRequestDiff(ctx context.Context, orgSlug, repoSlug, prSlug string) (string, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%s/diff", orgSlug, repoSlug, prSlug)
+
+	// Diff — текст, а не JSON, поэтому вызываем send напрямую
+	header := http.Header{"Accept": {"text/x-diff, text/plain;q=0.9"}}
+	resp, data, err := c.send(ctx, http.MethodGet, path, header, nil)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		// Некоторые серверы заворачивают diff в JSON: {"diff": "..."}
+		var wrapped struct {
+			Diff *string `json:"diff"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil || wrapped.Diff == nil {
+			snippet := string(data)
+			if len(snippet) > 150 {
+				snippet = snippet[:150] + "..."
+			}
+			return "", fmt.Errorf("unexpected JSON response from GET %s: %s", path, snippet)
+		}
+		return *wrapped.Diff, nil
+	}
+	return string(data), nil
+}
+
+// ListRepositoryIssues ('src issue list')
+// (GET /repos/{org_slug}/{repo_slug}/issues)
+// Ответ по Swagger: ListRepositoryIssuesResponse
+func (c *Client) ListRepositoryIssues(ctx context.Context, orgSlug, repoSlug string, opts ListOptions) ([]Issue, error) {
+	path := fmt.Sprintf("/repos/%s/%s/issues", orgSlug, repoSlug)
+	// TODO: Добавить query-параметры для фильтрации (state=open, etc.)
+	return collect(NewPaginator[Issue](ctx, c, path, "issues", opts), opts)
 }
 
 // CreateIssue ('src issue create')
 // (POST /repos/{org_slug}/{repo_slug}/issues)
-func (c *Client) CreateIssue(orgSlug, repoSlug string, body CreateIssueBody) (*Issue, error) {
+func (c *Client) CreateIssue(ctx context.Context, orgSlug, repoSlug string, body CreateIssueBody) (*Issue, error) {
 	path := fmt.Sprintf("/repos/%s/%s/issues", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodPost, path, body)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -689,12 +805,12 @@ func (c *Client) CreateIssue(orgSlug, repoSlug string, body CreateIssueBody) (*I
 
 // GetIssue ('src issue view <id>')
 // (GET /repos/{org_slug}/{repo_slug}/issues/{issue_slug})
-func (c *Client) GetIssue(orgSlug, repoSlug, issueSlug string) (*Issue, error) {
+func (c *Client) GetIssue(ctx context.Context, orgSlug, repoSlug, issueSlug string) (*Issue, error) {
 	path := fmt.Sprintf("/repos/%s/%s/issues/%s", orgSlug, repoSlug, issueSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
-			return nil, fmt.Errorf("issue '%s/%s#%s' not found or you don't have permission", orgSlug, repoSlug, issueSlug)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("issue '%s/%s#%s' not found or you don't have permission: %w", orgSlug, repoSlug, issueSlug, err)
 		}
 		return nil, err
 	}
@@ -711,9 +827,9 @@ func (c *Client) GetIssue(orgSlug, repoSlug, issueSlug string) (*Issue, error) {
 
 // UpdateIssue ('src issue update <id>' и 'src issue close <id>')
 // (PATCH /repos/{org_slug}/{repo_slug}/issues/{issue_slug})
-func (c *Client) UpdateIssue(orgSlug, repoSlug, issueSlug string, body UpdateIssueBody) (*Issue, error) {
+func (c *Client) UpdateIssue(ctx context.Context, orgSlug, repoSlug, issueSlug string, body UpdateIssueBody) (*Issue, error) {
 	path := fmt.Sprintf("/repos/%s/%s/issues/%s", orgSlug, repoSlug, issueSlug)
-	respBody, err := c.makeRequest(http.MethodPatch, path, body)
+	respBody, err := c.makeRequest(ctx, http.MethodPatch, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -728,35 +844,19 @@ func (c *Client) UpdateIssue(orgSlug, repoSlug, issueSlug string, body UpdateIss
 	return &updatedIssue, nil
 }
 
-func (c *Client) ListMilestonesForRepository(orgSlug, repoSlug string) ([]Milestone, error) {
+// ListMilestonesForRepository ('src milestone list')
+// (GET /repos/{org_slug}/{repo_slug}/milestones)
+// Ответ по Swagger: ListMilestonesForRepositoryResponse
+func (c *Client) ListMilestonesForRepository(ctx context.Context, orgSlug, repoSlug string, opts ListOptions) ([]Milestone, error) {
 	path := fmt.Sprintf("/repos/%s/%s/milestones", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Ответ по Swagger: ListMilestonesForRepositoryResponse
-	var response struct {
-		Items         []Milestone `json:"items"`
-		NextPageToken *string     `json:"next_page_token"`
-	}
-
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		snippet := string(respBody)
-		if len(snippet) > 150 {
-			snippet = snippet[:150] + "..."
-		}
-		return nil, fmt.Errorf("failed to decode milestone list JSON from GET %s: %w. Response start: %s", path, err, snippet)
-	}
-	// TODO: Handle pagination
-	return response.Items, nil
+	return collect(NewPaginator[Milestone](ctx, c, path, "items", opts), opts)
 }
 
 // CreateMilestone ('src milestone create')
 // (POST /repos/{org_slug}/{repo_slug}/milestones)
-func (c *Client) CreateMilestone(orgSlug, repoSlug string, body CreateMilestoneBody) (*Milestone, error) {
+func (c *Client) CreateMilestone(ctx context.Context, orgSlug, repoSlug string, body CreateMilestoneBody) (*Milestone, error) {
 	path := fmt.Sprintf("/repos/%s/%s/milestones", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodPost, path, body)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -773,12 +873,12 @@ func (c *Client) CreateMilestone(orgSlug, repoSlug string, body CreateMilestoneB
 
 // GetMilestone ('src milestone view <id>')
 // (GET /repos/{org_slug}/{repo_slug}/milestones/{milestone_slug})
-func (c *Client) GetMilestone(orgSlug, repoSlug, milestoneSlug string) (*Milestone, error) {
+func (c *Client) GetMilestone(ctx context.Context, orgSlug, repoSlug, milestoneSlug string) (*Milestone, error) {
 	path := fmt.Sprintf("/repos/%s/%s/milestones/%s", orgSlug, repoSlug, milestoneSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
-			return nil, fmt.Errorf("milestone '%s' in '%s/%s' not found or you don't have permission", milestoneSlug, orgSlug, repoSlug)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("milestone '%s' in '%s/%s' not found or you don't have permission: %w", milestoneSlug, orgSlug, repoSlug, err)
 		}
 		return nil, err
 	}
@@ -793,9 +893,9 @@ func (c *Client) GetMilestone(orgSlug, repoSlug, milestoneSlug string) (*Milesto
 	return &milestone, nil
 }
 
-func (c *Client) RunWorkflow(orgSlug, repoSlug, workflowName string, body RunCIBody) (*RunCIWorkflowResponse, error) {
+func (c *Client) RunWorkflow(ctx context.Context, orgSlug, repoSlug, workflowName string, body RunCIBody) (*RunCIWorkflowResponse, error) {
 	path := fmt.Sprintf("/%s/%s/cicd/runs", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodPost, path, body)
+	respBody, err := c.makeRequest(ctx, http.MethodPost, path, body)
 	if err != nil {
 		// API может вернуть 404, если workflow 'workflowName' не найден
 		return nil, err
@@ -812,31 +912,17 @@ func (c *Client) RunWorkflow(orgSlug, repoSlug, workflowName string, body RunCIB
 	return &runResponse, nil
 }
 
-func (c *Client) ListRepoRoles(orgSlug, repoSlug string) ([]SubjectRole, error) {
+// ListRepoRoles ('src access role list')
+// (GET /repos/{org_slug}/{repo_slug}/roles), see ListRepoRolesResponse
+func (c *Client) ListRepoRoles(ctx context.Context, orgSlug, repoSlug string, opts ListOptions) ([]SubjectRole, error) {
 	path := fmt.Sprintf("/repos/%s/%s/roles", orgSlug, repoSlug)
-	// TODO: Add pagination parameters later if needed
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Адаптируем под ожидаемый (но неявно заданный) ответ
-	var response ListRepoRolesResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		snippet := string(respBody)
-		if len(snippet) > 150 {
-			snippet = snippet[:150] + "..."
-		}
-		return nil, fmt.Errorf("failed to decode repo roles list JSON from GET %s: %w. Response start: %s", path, err, snippet)
-	}
-	// TODO: Handle pagination via response.NextPageToken
-	return response.SubjectRoles, nil
+	return collect(NewPaginator[SubjectRole](ctx, c, path, "subject_roles", opts), opts)
 }
 
 // AddRepoRole ('src access role add <repo> <user_id> <role>')
 // (POST /repos/{org_slug}/{repo_slug}/roles)
 // Примечание: API принимает массив, но для CLI удобнее добавлять по одному.
-func (c *Client) AddRepoRole(orgSlug, repoSlug string, userID string, role RepoRole) error {
+func (c *Client) AddRepoRole(ctx context.Context, orgSlug, repoSlug string, userID string, role RepoRole) error {
 	path := fmt.Sprintf("/repos/%s/%s/roles", orgSlug, repoSlug)
 	body := AddRepoRolesBody{
 		SubjectRoles: []SubjectRole{
@@ -849,7 +935,7 @@ func (c *Client) AddRepoRole(orgSlug, repoSlug string, userID string, role RepoR
 			},
 		},
 	}
-	_, err := c.makeRequest(http.MethodPost, path, body)
+	_, err := c.makeRequest(ctx, http.MethodPost, path, body)
 	// Swagger говорит 200 OK, но тело ответа не определено, поэтому игнорируем его.
 	if err != nil {
 		// API может вернуть 400 Bad Request при неверном role/user_id, 403 Forbidden, 404 Not Found
@@ -860,7 +946,7 @@ func (c *Client) AddRepoRole(orgSlug, repoSlug string, userID string, role RepoR
 
 // RemoveRepoRole ('src access role remove <repo> <user_id> <role>')
 // (POST /repos/{org_slug}/{repo_slug}/roles/remove)
-func (c *Client) RemoveRepoRole(orgSlug, repoSlug string, userID string, role RepoRole) error {
+func (c *Client) RemoveRepoRole(ctx context.Context, orgSlug, repoSlug string, userID string, role RepoRole) error {
 	path := fmt.Sprintf("/repos/%s/%s/roles/remove", orgSlug, repoSlug)
 	body := RemoveRepoRolesBody{
 		SubjectRoles: []SubjectRole{
@@ -873,7 +959,7 @@ func (c *Client) RemoveRepoRole(orgSlug, repoSlug string, userID string, role Re
 			},
 		},
 	}
-	_, err := c.makeRequest(http.MethodPost, path, body)
+	_, err := c.makeRequest(ctx, http.MethodPost, path, body)
 	// Swagger говорит 200 OK, тело ответа не определено.
 	if err != nil {
 		return err
@@ -881,31 +967,19 @@ func (c *Client) RemoveRepoRole(orgSlug, repoSlug string, userID string, role Re
 	return nil
 }
 
-func (c *Client) ListRuns(orgSlug, repoSlug string) ([]RunStatus, error) {
+// ListRuns ('src workflow list') - GET /{org_slug}/{repo_slug}/cicd/runs, see ListRunsResponse
+func (c *Client) ListRuns(ctx context.Context, orgSlug, repoSlug string, opts ListOptions) ([]RunStatus, error) {
 	path := fmt.Sprintf("/%s/%s/cicd/runs", orgSlug, repoSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var response ListRunsResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		snippet := string(respBody)
-		if len(snippet) > 150 {
-			snippet = snippet[:150] + "..."
-		}
-		return nil, fmt.Errorf("failed to decode run list JSON from GET %s: %w. Response start: %s", path, err, snippet)
-	}
-	return response.Runs, nil
+	return collect(NewPaginator[RunStatus](ctx, c, path, "runs", opts), opts)
 }
 
 // GetRunStatus ('src workflow status <run_slug>') - GET /{org_slug}/{repo_slug}/cicd/runs/{run_slug}
-func (c *Client) GetRunStatus(orgSlug, repoSlug, runSlug string) (*RunStatus, error) {
+func (c *Client) GetRunStatus(ctx context.Context, orgSlug, repoSlug, runSlug string) (*RunStatus, error) {
 	path := fmt.Sprintf("/%s/%s/cicd/runs/%s", orgSlug, repoSlug, runSlug)
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "404 Not Found") {
-			return nil, fmt.Errorf("run '%s' in '%s/%s' not found or you don't have permission", runSlug, orgSlug, repoSlug)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("run '%s' in '%s/%s' not found or you don't have permission: %w", runSlug, orgSlug, repoSlug, err)
 		}
 		return nil, err
 	}
@@ -917,7 +991,7 @@ func (c *Client) GetRunStatus(orgSlug, repoSlug, runSlug string) (*RunStatus, er
 }
 
 // GetLogs - GET /{org_slug}/{repo_slug}/cicd/logs/{run_slug}/{workflow_slug}/{task_slug}/{cube_slug}
-func (c *Client) GetLogs(orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug string) (string, error) {
+func (c *Client) GetLogs(ctx context.Context, orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug string) (string, error) {
 	path := fmt.Sprintf("/%s/%s/cicd/logs/%s/%s/%s/%s", orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
 
 	// Используем makeRequest, но ожидаем, что ответ может быть text/plain (логи)
@@ -929,7 +1003,7 @@ func (c *Client) GetLogs(orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cub
 	// Оставим пока так, предполагая, что API может возвращать JSON-обертку вокруг логов.
 	// Если API возвращает чистый текст, makeRequest выдаст ошибку, которую нужно будет отловить.
 
-	respBody, err := c.makeRequest(http.MethodGet, path, nil)
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return "", err
 	}
@@ -946,34 +1020,90 @@ func (c *Client) GetLogs(orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cub
 }
 
 // GetArtifacts - GET /{org_slug}/{repo_slug}/cicd/artifacts/{run_slug}/{workflow_slug}/{task_slug}/{cube_slug}
-func (c *Client) GetArtifacts(orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug string) ([]byte, error) {
+func (c *Client) GetArtifacts(ctx context.Context, orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug string) ([]byte, error) {
 	path := fmt.Sprintf("/%s/%s/cicd/artifacts/%s/%s/%s/%s", orgSlug, repoSlug, runSlug, workflowSlug, taskSlug, cubeSlug)
 
-	// Для артефактов обычно требуется отдельный HTTP-клиент, который
-	// не накладывает жестких ограничений на Content-Type (например, application/octet-stream).
+	// Артефакты не обязательно JSON (например, application/octet-stream),
+	// поэтому вызываем send напрямую, без проверки Content-Type
+	header := http.Header{"Accept": {"*/*"}}
+	_, data, err := c.send(ctx, http.MethodGet, path, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
 
-	// Временно используем makeRequest, но без проверки Content-Type
-	req, err := http.NewRequest(http.MethodGet, c.BaseURL+path, nil)
+// CurrentUser is the owner of the token, as returned by GET /me.
+type CurrentUser struct {
+	ID    *string `json:"id"`
+	Slug  *string `json:"slug"`
+	Name  *string `json:"name,omitempty"`
+	Email *string `json:"email,omitempty"`
+}
+
+// TokenInfo describes the token used for a request. The fields are filled
+// only if the server reports them in the X-Token-Scopes and X-Token-Expires-At
+// response headers.
+type TokenInfo struct {
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// GetUser ищет пользователя по slug (логину): 'src pr create --reviewer alice'
+// (GET /users/{user_slug})
+func (c *Client) GetUser(ctx context.Context, userSlug string) (*User, error) {
+	path := fmt.Sprintf("/users/%s", url.PathEscape(userSlug))
+	respBody, err := c.makeRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("request creation error: %w", err)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("user '%s' not found: %w", userSlug, err)
+		}
+		return nil, err
 	}
+	var user User
+	if err := json.Unmarshal(respBody, &user); err != nil {
+		snippet := string(respBody)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return nil, fmt.Errorf("failed to decode user JSON from GET %s: %w. Response start: %s", path, err, snippet)
+	}
+	return &user, nil
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	resp, err := c.HTTPClient.Do(req)
+// GetCurrentUser - GET /me. Returns the user the token belongs to; a bad
+// token fails with an error matching ErrUnauthorized.
+func (c *Client) GetCurrentUser(ctx context.Context) (*CurrentUser, *TokenInfo, error) {
+	path := "/me"
+	resp, respBody, err := c.send(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("request execution error: %w", err)
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body) // Пытаемся прочитать ошибку
-		return nil, fmt.Errorf("API returned error: %s (path: %s). Body: %s", resp.Status, path, string(respBody))
+	var user CurrentUser
+	if err := json.Unmarshal(respBody, &user); err != nil {
+		snippet := string(respBody)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return nil, nil, fmt.Errorf("failed to decode user JSON from GET %s: %w. Response start: %s", path, err, snippet)
 	}
 
-	// Читаем сырые данные
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read artifact data: %w", err)
+	info := &TokenInfo{}
+	for _, h := range []string{"X-Token-Scopes", "X-OAuth-Scopes"} {
+		for _, scope := range strings.Split(resp.Header.Get(h), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+		if len(info.Scopes) > 0 {
+			break
+		}
 	}
-	return data, nil
+	if v := resp.Header.Get("X-Token-Expires-At"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			info.ExpiresAt = &t
+		}
+	}
+	return &user, info, nil
 }
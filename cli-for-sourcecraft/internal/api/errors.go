@@ -0,0 +1,156 @@
+// internal/api/errors.go
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for errors.Is checks, e.g. errors.Is(err, api.ErrNotFound).
+// An *Error matches the sentinel that corresponds to its status code.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+	ErrNetwork      = errors.New("network error")
+)
+
+// Error is a non-2xx response from the SourceCraft API.
+type Error struct {
+	StatusCode int           // HTTP status code, e.g. 404
+	Status     string        // HTTP status line, e.g. "404 Not Found"
+	Message    string        // 'message' from the JSON error body
+	RequestID  string        // 'request_id' from the JSON error body
+	Method     string        // Request method
+	Path       string        // Request path (without the base URL)
+	RetryAfter time.Duration // Retry-After hint (0 if absent)
+	Body       string        // Start of the raw body if it was not a JSON error
+}
+
+func (e *Error) Error() string {
+	var msg string
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		msg = fmt.Sprintf("API error: %s (Invalid or expired token, path: %s)", e.Status, e.Path)
+	case http.StatusNotFound:
+		msg = fmt.Sprintf("API error: %s (Incorrect path: %s)", e.Status, e.Path)
+	case http.StatusMethodNotAllowed:
+		msg = fmt.Sprintf("API error: %s (Wrong HTTP method for path: %s)", e.Status, e.Path)
+	case http.StatusTooManyRequests:
+		msg = fmt.Sprintf("API error: %s (Rate limit exceeded, path: %s)", e.Status, e.Path)
+		if e.RetryAfter > 0 {
+			msg = fmt.Sprintf("%s. Retry after %v", msg, e.RetryAfter)
+		}
+	default:
+		msg = fmt.Sprintf("API returned error: %s (path: %s)", e.Status, e.Path)
+	}
+
+	if e.Message != "" {
+		if e.RequestID != "" {
+			return fmt.Sprintf("%s. Message: %s (Request ID: %s)", msg, e.Message, e.RequestID)
+		}
+		return fmt.Sprintf("%s. Message: %s", msg, e.Message)
+	}
+	if e.Body != "" {
+		return fmt.Sprintf("%s. Response Body: %s", msg, e.Body)
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrNotFound) and friends work for *Error.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// NetworkError is a request that never got an HTTP response
+// (DNS failure, refused connection, TLS error, timeout).
+type NetworkError struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("request execution error (%s %s): %v", e.Method, e.Path, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrNetwork) work for *NetworkError.
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
+// newError builds an *Error from a non-2xx response whose body was already read.
+func newError(resp *http.Response, method, path string, body []byte) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Method:     method,
+		Path:       path,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if len(body) == 0 {
+		return apiErr
+	}
+
+	var jsonError struct {
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+	}
+	if json.Unmarshal(body, &jsonError) == nil && jsonError.Message != "" {
+		apiErr.Message = jsonError.Message
+		apiErr.RequestID = jsonError.RequestID
+		return apiErr
+	}
+
+	snippet := strings.TrimSpace(string(body))
+	if len(snippet) > 500 {
+		snippet = snippet[:500] + "..."
+	}
+	apiErr.Body = snippet
+	return apiErr
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds
+// ("120") or as an HTTP-date ("Wed, 21 Oct 2015 07:28:00 GMT").
+// A date in the past yields 0.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d.Round(time.Second)
+		}
+	}
+	return 0
+}
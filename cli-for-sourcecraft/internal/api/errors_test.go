@@ -0,0 +1,92 @@
+// internal/api/errors_test.go
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var sentinels = []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrRateLimited, ErrServer, ErrNetwork}
+
+// statusServer answers every request with status and a JSON error body.
+func statusServer(t *testing.T, status int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"message": "status ` + strconv.Itoa(status) + `", "request_id": "req-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error // nil — ни один sentinel
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusBadGateway, ErrServer},
+		{http.StatusServiceUnavailable, ErrServer},
+		{http.StatusBadRequest, nil},
+		{http.StatusUnprocessableEntity, nil},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			c := newTestClient(statusServer(t, tt.status))
+			c.Retry.MaxAttempts = 1
+
+			// GetPullRequest оборачивает 404 через fmt.Errorf(... %w), остальные коды возвращает как есть
+			_, err := c.GetPullRequest(context.Background(), "org", "repo", "7")
+			if err == nil {
+				t.Fatal("GetPullRequest() error = nil")
+			}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, sentinel, got)
+				}
+			}
+			if _, wrapped := err.(*Error); wrapped == (tt.status == http.StatusNotFound) {
+				t.Errorf("error %T: want 404 wrapped by GetPullRequest and other codes returned as *Error", err)
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("errors.As(%v, *Error) = false", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != "status "+strconv.Itoa(tt.status) ||
+				apiErr.RequestID != "req-1" || apiErr.Method != http.MethodGet || apiErr.Path != "/repos/org/repo/pulls/7" {
+				t.Errorf("*Error = %+v, want the status, message, request ID, method and path of the response", apiErr)
+			}
+		})
+	}
+}
+
+func TestNetworkErrorSentinel(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	c := newTestClient(srv)
+	c.Retry.MaxAttempts = 1
+	srv.Close() // Соединение будет отклонено
+
+	_, err := c.GetPullRequest(context.Background(), "org", "repo", "7")
+	for _, sentinel := range sentinels {
+		if got := errors.Is(err, sentinel); got != (sentinel == ErrNetwork) {
+			t.Errorf("errors.Is(%v, %v) = %v", err, sentinel, got)
+		}
+	}
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) || netErr.Method != http.MethodGet || netErr.Path != "/repos/org/repo/pulls/7" {
+		t.Errorf("errors.As(%v, *NetworkError) = %+v, want the method and path of the request", err, netErr)
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		t.Errorf("network error %v also matches *Error", err)
+	}
+}
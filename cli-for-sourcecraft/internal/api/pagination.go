@@ -0,0 +1,187 @@
+// internal/api/pagination.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultListLimit is how many items a List* method returns when the caller
+// sets neither ListOptions.Limit nor ListOptions.All.
+const DefaultListLimit = 30
+
+// maxPageSize caps the page_size we ask for, so a large --limit still
+// walks several reasonably sized pages instead of one huge response.
+const maxPageSize = 100
+
+// ListOptions controls how many items a List* method collects.
+type ListOptions struct {
+	Limit    int  // Maximum number of items to return (<= 0 means DefaultListLimit)
+	All      bool // Follow next_page_token to the last page, ignoring Limit
+	PageSize int  // page_size query parameter (0 = derived from Limit)
+}
+
+// limit returns the effective item limit, or -1 if there is none.
+func (o ListOptions) limit() int {
+	if o.All {
+		return -1
+	}
+	if o.Limit <= 0 {
+		return DefaultListLimit
+	}
+	return o.Limit
+}
+
+// pageSize returns the page_size to request, or 0 to leave it to the server.
+func (o ListOptions) pageSize() int {
+	if o.PageSize > 0 {
+		return o.PageSize
+	}
+	if limit := o.limit(); limit > 0 && limit < maxPageSize {
+		return limit
+	}
+	return maxPageSize
+}
+
+// Paginator walks a cursor-paginated list endpoint. Every SourceCraft list
+// response has the shape {"<items>": [...], "next_page_token": "..."}; the
+// paginator requests the next page with ?page_token=... until the server
+// stops returning a token.
+//
+//	p := api.NewPaginator[api.Repo](ctx, client, "/orgs/my-org/repos", "repositories", api.ListOptions{All: true})
+//	for p.Next() {
+//		repo := p.Item()
+//	}
+//	if err := p.Err(); err != nil { ... }
+type Paginator[T any] struct {
+	ctx      context.Context
+	client   *Client
+	path     string
+	itemsKey string
+	pageSize int
+
+	started   bool
+	nextToken string
+	page      []T
+	idx       int
+	current   T
+	err       error
+}
+
+// NewPaginator creates a paginator over the list endpoint at path. itemsKey
+// is the JSON field that holds the page items (e.g. "repositories").
+// Every page request is bound to ctx.
+func NewPaginator[T any](ctx context.Context, c *Client, path, itemsKey string, opts ListOptions) *Paginator[T] {
+	return &Paginator[T]{
+		ctx:      ctx,
+		client:   c,
+		path:     path,
+		itemsKey: itemsKey,
+		pageSize: opts.pageSize(),
+	}
+}
+
+// Next advances to the next item, fetching the next page when the current
+// one is exhausted. It returns false when there are no more items or an
+// error occurred (check Err).
+func (p *Paginator[T]) Next() bool {
+	for p.idx >= len(p.page) {
+		if p.err != nil || (p.started && p.nextToken == "") {
+			return false
+		}
+		if err := p.fetchPage(); err != nil {
+			p.err = err
+			return false
+		}
+	}
+	p.current = p.page[p.idx]
+	p.idx++
+	return true
+}
+
+// Item returns the item Next advanced to.
+func (p *Paginator[T]) Item() T {
+	return p.current
+}
+
+// Err returns the first error encountered while fetching pages.
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+func (p *Paginator[T]) fetchPage() error {
+	query := url.Values{}
+	if p.nextToken != "" {
+		query.Set("page_token", p.nextToken)
+	}
+	if p.pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(p.pageSize))
+	}
+	path := p.path
+	if len(query) > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + query.Encode()
+	}
+
+	respBody, err := p.client.makeRequest(p.ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		snippet := string(respBody)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return fmt.Errorf("failed to decode %s page JSON from GET %s: %w. Response start: %s", p.itemsKey, path, err, snippet)
+	}
+
+	var items []T
+	if raw, ok := response[p.itemsKey]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return fmt.Errorf("failed to decode '%s' from GET %s: %w", p.itemsKey, path, err)
+		}
+	}
+	var next *string
+	if raw, ok := response["next_page_token"]; ok {
+		if err := json.Unmarshal(raw, &next); err != nil {
+			return fmt.Errorf("failed to decode 'next_page_token' from GET %s: %w", path, err)
+		}
+	}
+
+	prevToken := p.nextToken
+	p.started = true
+	p.page = items
+	p.idx = 0
+	p.nextToken = ""
+	if next != nil {
+		p.nextToken = *next
+	}
+	// Защита от бесконечного цикла, если сервер возвращает тот же токен
+	if p.nextToken != "" && p.nextToken == prevToken {
+		return fmt.Errorf("API returned the same next_page_token twice for GET %s", p.path)
+	}
+	return nil
+}
+
+// collect drains p into a slice, stopping early once opts' limit is reached.
+func collect[T any](p *Paginator[T], opts ListOptions) ([]T, error) {
+	limit := opts.limit()
+	items := []T{}
+	for (limit < 0 || len(items) < limit) && p.Next() {
+		items = append(items, p.Item())
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,185 @@
+// internal/api/pagination_test.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves total repositories from GET /orgs/o/repos, at most
+// perPage per page, with next_page_token set to the offset of the next page.
+type pagedServer struct {
+	total   int
+	perPage int
+
+	mu       sync.Mutex
+	requests []string // RawQuery каждого запроса
+}
+
+func (s *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RawQuery)
+	s.mu.Unlock()
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+	size := s.perPage
+	if n, err := strconv.Atoi(r.URL.Query().Get("page_size")); err == nil && n < size {
+		size = n
+	}
+	end := min(offset+size, s.total)
+
+	type repo struct {
+		Slug string `json:"slug"`
+	}
+	page := struct {
+		Repositories  []repo  `json:"repositories"`
+		NextPageToken *string `json:"next_page_token,omitempty"`
+	}{Repositories: []repo{}}
+	for i := offset; i < end; i++ {
+		page.Repositories = append(page.Repositories, repo{Slug: "repo-" + strconv.Itoa(i)})
+	}
+	if end < s.total {
+		next := strconv.Itoa(end)
+		page.NextPageToken = &next
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func repoSlugs(repos []Repo) []string {
+	slugs := make([]string, len(repos))
+	for i, r := range repos {
+		slugs[i] = *r.Slug
+	}
+	return slugs
+}
+
+func TestListRepositoriesPaging(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		perPage  int
+		opts     ListOptions
+		want     int // число элементов
+		requests int
+	}{
+		{"single page", 2, 10, ListOptions{}, 2, 1},
+		{"follows next_page_token", 7, 3, ListOptions{Limit: 50}, 7, 3},
+		{"limit at a page boundary", 9, 3, ListOptions{Limit: 6}, 6, 2},
+		{"limit in the middle of a page", 9, 4, ListOptions{Limit: 5}, 5, 2},
+		{"default limit", 40, 100, ListOptions{}, DefaultListLimit, 1},
+		{"all", 250, 100, ListOptions{All: true}, 250, 3},
+		{"all ignores limit", 7, 3, ListOptions{All: true, Limit: 2}, 7, 3},
+		{"empty list", 0, 3, ListOptions{All: true}, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &pagedServer{total: tt.total, perPage: tt.perPage}
+			srv := httptest.NewServer(ps)
+			defer srv.Close()
+
+			repos, err := newTestClient(srv).ListRepositories(context.Background(), "o", tt.opts)
+			if err != nil {
+				t.Fatalf("ListRepositories() error = %v", err)
+			}
+			if len(repos) != tt.want {
+				t.Fatalf("got %d repositories, want %d", len(repos), tt.want)
+			}
+			for i, slug := range repoSlugs(repos) {
+				if slug != "repo-"+strconv.Itoa(i) {
+					t.Fatalf("repository %d is %s, want items in server order without gaps", i, slug)
+				}
+			}
+			if len(ps.requests) != tt.requests {
+				t.Errorf("server got %d requests (%v), want %d", len(ps.requests), ps.requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestPaginatorPageSize(t *testing.T) {
+	tests := []struct {
+		opts ListOptions
+		want string
+	}{
+		{ListOptions{Limit: 5}, "page_size=5"},
+		{ListOptions{}, "page_size=" + strconv.Itoa(DefaultListLimit)},
+		{ListOptions{Limit: 500}, "page_size=" + strconv.Itoa(maxPageSize)},
+		{ListOptions{All: true}, "page_size=" + strconv.Itoa(maxPageSize)},
+		{ListOptions{Limit: 5, PageSize: 2}, "page_size=2"},
+	}
+	for _, tt := range tests {
+		ps := &pagedServer{total: 1, perPage: 10}
+		srv := httptest.NewServer(ps)
+		if _, err := newTestClient(srv).ListRepositories(context.Background(), "o", tt.opts); err != nil {
+			t.Errorf("ListRepositories(%+v) error = %v", tt.opts, err)
+		} else if len(ps.requests) != 1 || ps.requests[0] != tt.want {
+			t.Errorf("ListRepositories(%+v) sent %v, want %q", tt.opts, ps.requests, tt.want)
+		}
+		srv.Close()
+	}
+}
+
+func TestPaginatorSendsPageToken(t *testing.T) {
+	ps := &pagedServer{total: 5, perPage: 2}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+
+	p := NewPaginator[Repo](context.Background(), newTestClient(srv), "/orgs/o/repos?visibility=public", "repositories", ListOptions{All: true})
+	n := 0
+	for p.Next() {
+		n++
+	}
+	if err := p.Err(); err != nil || n != 5 {
+		t.Fatalf("paginator returned %d items, error %v; want 5 items", n, err)
+	}
+	want := []string{
+		"visibility=public&page_size=100",
+		"visibility=public&page_size=100&page_token=2",
+		"visibility=public&page_size=100&page_token=4",
+	}
+	if strings.Join(ps.requests, " ") != strings.Join(want, " ") {
+		t.Errorf("queries = %v, want %v", ps.requests, want)
+	}
+}
+
+func TestPaginatorRepeatedTokenGuard(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": [{"slug": "a"}], "next_page_token": "same"}`))
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv).ListRepositories(context.Background(), "o", ListOptions{All: true})
+	if err == nil || !strings.Contains(err.Error(), "same next_page_token") {
+		t.Fatalf("ListRepositories() error = %v, want the repeated token error", err)
+	}
+	if requests != 2 {
+		t.Errorf("server got %d requests, want 2", requests)
+	}
+}
+
+func TestPaginatorStopsOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page_token") != "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": [{"slug": "a"}], "next_page_token": "2"}`))
+	}))
+	defer srv.Close()
+
+	repos, err := newTestClient(srv).ListRepositories(context.Background(), "o", ListOptions{All: true})
+	if repos != nil || err == nil {
+		t.Fatalf("ListRepositories() = %v, %v; want an error and no items", repos, err)
+	}
+}
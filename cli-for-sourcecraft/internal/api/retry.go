@@ -0,0 +1,90 @@
+// internal/api/retry.go
+package api
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader marks a request as safe to retry even if its method
+// is not idempotent: the server deduplicates requests with the same key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy controls how makeRequest retries failed requests.
+//
+// A request is retried after a network error or a 429/502/503/504 response,
+// but only if repeating it cannot create a duplicate: the method is
+// idempotent (GET, HEAD, OPTIONS, PUT, DELETE) or the request carries an
+// Idempotency-Key header. A 429 is retried for any method, since the server
+// rejected the request without processing it.
+type RetryPolicy struct {
+	MaxAttempts    int           // Total attempts including the first one (1 = no retries)
+	InitialBackoff time.Duration // Wait before the first retry, doubled for each next one
+	MaxBackoff     time.Duration // Upper bound for a single wait, including Retry-After
+}
+
+// DefaultRetryPolicy is used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 1 * time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
+// normalized fills zero or invalid fields with the defaults.
+func (p RetryPolicy) normalized() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	return p
+}
+
+// backoff returns the wait before retry number attempt (0-based):
+// InitialBackoff*2^attempt capped at MaxBackoff, with "equal jitter" —
+// a random value between half and the full delay, so that many clients
+// hitting the same limit do not retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 0; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half+1)
+}
+
+// retryableStatus reports whether a response status is worth retrying.
+// idempotent says whether the request may be safely repeated after the
+// server could have processed it.
+func retryableStatus(code int, idempotent bool) bool {
+	switch code {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// isIdempotent reports whether a request can be repeated without side effects.
+func isIdempotent(method string, header http.Header) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return header.Get(IdempotencyKeyHeader) != ""
+}
@@ -0,0 +1,326 @@
+// internal/api/retry_test.go
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy keeps the backoff short so the tests do not sleep for seconds.
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// newTestClient returns a client for srv with a fast retry policy.
+func newTestClient(srv *httptest.Server) *Client {
+	c := NewClient(srv.URL, "test-token")
+	c.HTTPClient = srv.Client()
+	c.Retry = testRetryPolicy
+	return c
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// closeTracker counts Close calls on response bodies.
+type closeTracker struct {
+	io.ReadCloser
+	closed *atomic.Int32
+}
+
+func (b closeTracker) Close() error {
+	b.closed.Add(1)
+	return b.ReadCloser.Close()
+}
+
+func TestIsIdempotent(t *testing.T) {
+	withKey := http.Header{}
+	withKey.Set(IdempotencyKeyHeader, "abc")
+
+	tests := []struct {
+		method string
+		header http.Header
+		want   bool
+	}{
+		{http.MethodGet, nil, true},
+		{http.MethodHead, nil, true},
+		{http.MethodOptions, nil, true},
+		{http.MethodPut, nil, true},
+		{http.MethodDelete, nil, true},
+		{http.MethodPost, nil, false},
+		{http.MethodPatch, nil, false},
+		{http.MethodPost, withKey, true},
+		{http.MethodPatch, withKey, true},
+	}
+	for _, tt := range tests {
+		if got := isIdempotent(tt.method, tt.header); got != tt.want {
+			t.Errorf("isIdempotent(%s, %v) = %v, want %v", tt.method, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	tests := []struct {
+		code       int
+		idempotent bool
+		want       bool
+	}{
+		{http.StatusTooManyRequests, true, true},
+		{http.StatusTooManyRequests, false, true},
+		{http.StatusBadGateway, true, true},
+		{http.StatusBadGateway, false, false},
+		{http.StatusServiceUnavailable, true, true},
+		{http.StatusServiceUnavailable, false, false},
+		{http.StatusGatewayTimeout, true, true},
+		{http.StatusGatewayTimeout, false, false},
+		{http.StatusInternalServerError, true, false},
+		{http.StatusNotFound, true, false},
+		{http.StatusOK, true, false},
+	}
+	for _, tt := range tests {
+		if got := retryableStatus(tt.code, tt.idempotent); got != tt.want {
+			t.Errorf("retryableStatus(%d, %v) = %v, want %v", tt.code, tt.idempotent, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{"empty", "", 0, 0},
+		{"delta-seconds", "120", 120 * time.Second, 120 * time.Second},
+		{"zero seconds", "0", 0, 0},
+		{"padded", " 5 ", 5 * time.Second, 5 * time.Second},
+		{"negative", "-5", 0, 0},
+		{"garbage", "soon", 0, 0},
+		{"fraction", "1.5", 0, 0},
+		{"HTTP-date in the future", time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat), 88 * time.Second, 90 * time.Second},
+		{"HTTP-date in the past", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRetryAfter(tt.value)
+			if got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 4 * time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		if d := p.backoff(attempt); d > p.MaxBackoff || d <= 0 {
+			t.Errorf("backoff(%d) = %v, want in (0, %v]", attempt, d, p.MaxBackoff)
+		}
+	}
+}
+
+func TestSendRetriesGatewayErrorsOnlyForIdempotentMethods(t *testing.T) {
+	tests := []struct {
+		method   string
+		status   int
+		key      bool // Idempotency-Key
+		attempts int32
+	}{
+		{http.MethodGet, http.StatusBadGateway, false, 3},
+		{http.MethodGet, http.StatusServiceUnavailable, false, 3},
+		{http.MethodGet, http.StatusGatewayTimeout, false, 3},
+		{http.MethodPut, http.StatusServiceUnavailable, false, 3},
+		{http.MethodDelete, http.StatusGatewayTimeout, false, 3},
+		{http.MethodPost, http.StatusBadGateway, false, 1},
+		{http.MethodPost, http.StatusServiceUnavailable, false, 1},
+		{http.MethodPost, http.StatusGatewayTimeout, false, 1},
+		{http.MethodPatch, http.StatusServiceUnavailable, false, 1},
+		{http.MethodPost, http.StatusServiceUnavailable, true, 3},
+		{http.MethodPost, http.StatusTooManyRequests, false, 3},
+		{http.MethodGet, http.StatusInternalServerError, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+http.StatusText(tt.status), func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			var header http.Header
+			if tt.key {
+				header = http.Header{IdempotencyKeyHeader: {"key-1"}}
+			}
+			_, _, err := newTestClient(srv).send(context.Background(), tt.method, "/x", header, nil)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("send() error = %v, want *Error with status %d", err, tt.status)
+			}
+			if got := calls.Load(); got != tt.attempts {
+				t.Errorf("server got %d requests, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestSendSucceedsAfterRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body) // тело должно передаваться при каждой попытке
+	}))
+	defer srv.Close()
+
+	_, body, err := newTestClient(srv).send(context.Background(), http.MethodPut, "/x", nil, []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if string(body) != `{"a":1}` {
+		t.Errorf("body = %q, want the request body echoed on the last attempt", body)
+	}
+}
+
+func TestSendDoesNotRetryPostOnNetworkError(t *testing.T) {
+	tests := []struct {
+		method   string
+		attempts int32
+	}{
+		{http.MethodPost, 1},
+		{http.MethodPatch, 1},
+		{http.MethodGet, 3},
+		{http.MethodDelete, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			var calls atomic.Int32
+			c := NewClient("http://sourcecraft.invalid", "test-token")
+			c.Retry = testRetryPolicy
+			c.HTTPClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				calls.Add(1)
+				return nil, errors.New("connection reset by peer")
+			})}
+
+			_, _, err := c.send(context.Background(), tt.method, "/x", nil, []byte(`{}`))
+			if !errors.Is(err, ErrNetwork) {
+				t.Fatalf("send() error = %v, want ErrNetwork", err)
+			}
+			if got := calls.Load(); got != tt.attempts {
+				t.Errorf("transport got %d requests, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestSendClosesEveryResponseBody(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("bad gateway"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var closed atomic.Int32
+	c := newTestClient(srv)
+	base := srv.Client().Transport
+	c.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := base.RoundTrip(req)
+		if err == nil {
+			resp.Body = closeTracker{ReadCloser: resp.Body, closed: &closed}
+		}
+		return resp, err
+	})}
+
+	if _, _, err := c.send(context.Background(), http.MethodGet, "/x", nil, nil); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if calls.Load() != 3 || closed.Load() != 3 {
+		t.Errorf("%d responses, %d bodies closed; want 3 and 3", calls.Load(), closed.Load())
+	}
+}
+
+func TestSendHonoursRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	if _, _, err := newTestClient(srv).send(context.Background(), http.MethodPost, "/x", nil, nil); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	// Без Retry-After пауза была бы ~1ms (InitialBackoff)
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %v, want at least the 1s from Retry-After", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestSendGivesUpWhenRetryAfterExceedsMaxBackoff(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	_, _, err := newTestClient(srv).send(context.Background(), http.MethodGet, "/x", nil, nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("send() error = %v, want a rate limit *Error", err)
+	}
+	if apiErr.RetryAfter != time.Hour {
+		t.Errorf("RetryAfter = %v, want 1h", apiErr.RetryAfter)
+	}
+	if calls.Load() != 1 || time.Since(start) > time.Second {
+		t.Errorf("server got %d requests in %v, want 1 without waiting", calls.Load(), time.Since(start))
+	}
+}
+
+func TestSendStopsRetryingWhenContextIsCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv)
+	c.Retry = RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Minute, MaxBackoff: time.Minute}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := c.send(ctx, http.MethodGet, "/x", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("send() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,409 @@
+// internal/api/trace.go
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Body size limits: maxLogBody for the stderr log, maxTraceBody for the trace file.
+const (
+	maxLogBody   = 2 * 1024
+	maxTraceBody = 64 * 1024
+)
+
+// redactedHeaders never appear in traces with their real value.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// secretJSONField matches token-like fields in JSON bodies (OAuth responses etc).
+var secretJSONField = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|token|client_secret|password|device_code)"\s*:\s*)"[^"]*"`)
+
+// secretFormField matches the same fields in form-encoded bodies (OAuth token requests).
+var secretFormField = regexp.MustCompile(`(^|&)((?:access_token|refresh_token|id_token|token|client_secret|password|device_code)=)[^&]*`)
+
+// TraceEntry is one request/response pair, as written to a JSON lines trace file.
+type TraceEntry struct {
+	Time            time.Time           `json:"time"`
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	Proto           string              `json:"proto,omitempty"`
+	Status          int                 `json:"status,omitempty"`
+	StatusText      string              `json:"status_text,omitempty"`
+	DurationMs      int64               `json:"duration_ms"`
+	RequestHeaders  map[string][]string `json:"request_headers"`
+	ResponseHeaders map[string][]string `json:"response_headers,omitempty"`
+	RequestBody     string              `json:"request_body,omitempty"`
+	ResponseBody    string              `json:"response_body,omitempty"`
+	ResponseSize    int64               `json:"response_size"`
+	Error           string              `json:"error,omitempty"`
+}
+
+// TraceRecorder stores trace entries, e.g. in a file.
+type TraceRecorder interface {
+	Record(entry *TraceEntry) error
+	Close() error
+}
+
+// TracingTransport is an http.RoundTripper that logs every request and
+// response to Log and/or hands it to Recorder. Authorization and cookie
+// headers, and token fields in JSON bodies, are redacted.
+type TracingTransport struct {
+	Base      http.RoundTripper // Underlying transport (nil = http.DefaultTransport)
+	Log       io.Writer         // Human-readable log (nil = off), usually os.Stderr
+	LogBodies bool              // Also log bodies (truncated)
+	Recorder  TraceRecorder     // Trace file (nil = off)
+
+	mu sync.Mutex
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	entry := &TraceEntry{
+		Time:           time.Now(),
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+	if req.GetBody != nil && req.ContentLength != 0 {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, maxTraceBody))
+			body.Close()
+			entry.RequestBody = redactBody(data)
+		}
+	}
+	t.logRequest(entry)
+
+	resp, err := base.RoundTrip(req)
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+	if err != nil {
+		entry.Error = err.Error()
+		t.logf("< error after %dms: %v\n\n", entry.DurationMs, err)
+		t.record(entry)
+		return nil, err
+	}
+
+	entry.Proto = resp.Proto
+	entry.Status = resp.StatusCode
+	entry.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+	entry.ResponseHeaders = redactHeaders(resp.Header)
+	t.logResponse(entry)
+
+	// Тело ответа фиксируем по мере чтения, запись в трассировку — при Close
+	resp.Body = &tracedBody{ReadCloser: resp.Body, transport: t, entry: entry}
+	return resp, nil
+}
+
+func (t *TracingTransport) logRequest(e *TraceEntry) {
+	if t.Log == nil {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "> %s %s\n", e.Method, e.URL)
+	writeHeaders(&b, "> ", e.RequestHeaders)
+	if t.LogBodies && e.RequestBody != "" {
+		fmt.Fprintf(&b, "> %s\n", truncate(e.RequestBody, maxLogBody))
+	}
+	t.logf("%s", b.String())
+}
+
+func (t *TracingTransport) logResponse(e *TraceEntry) {
+	if t.Log == nil {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "< %s %d %s (%dms)\n", e.Proto, e.Status, e.StatusText, e.DurationMs)
+	writeHeaders(&b, "< ", e.ResponseHeaders)
+	if !t.LogBodies {
+		b.WriteString("\n")
+	}
+	t.logf("%s", b.String())
+}
+
+func (t *TracingTransport) logf(format string, args ...interface{}) {
+	if t.Log == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.Log, format, args...)
+}
+
+func (t *TracingTransport) record(e *TraceEntry) {
+	if t.Recorder == nil {
+		return
+	}
+	if err := t.Recorder.Record(e); err != nil && t.Log != nil {
+		t.logf("Warning: failed to write trace file: %v\n", err)
+	}
+}
+
+// tracedBody keeps the beginning of the response body and finishes the
+// trace entry when the body is closed.
+type tracedBody struct {
+	io.ReadCloser
+	transport *TracingTransport
+	entry     *TraceEntry
+	buf       bytes.Buffer
+	size      int64
+	once      sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if room := maxTraceBody - b.buf.Len(); room > 0 && n > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.entry.ResponseSize = b.size
+		b.entry.ResponseBody = redactBody(b.buf.Bytes())
+		if b.transport.LogBodies {
+			if b.entry.ResponseBody != "" {
+				b.transport.logf("< %s\n\n", truncate(b.entry.ResponseBody, maxLogBody))
+			} else {
+				b.transport.logf("\n")
+			}
+		}
+		b.transport.record(b.entry)
+	})
+	return err
+}
+
+func redactHeaders(h http.Header) map[string][]string {
+	out := make(map[string][]string, len(h))
+	for key, values := range h {
+		if redactedHeaders[http.CanonicalHeaderKey(key)] {
+			redacted := make([]string, len(values))
+			for i, v := range values {
+				redacted[i] = redactValue(v)
+			}
+			out[key] = redacted
+			continue
+		}
+		out[key] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// redactValue keeps the auth scheme ("Bearer") and hides the credentials.
+func redactValue(v string) string {
+	if scheme, _, ok := strings.Cut(v, " "); ok && !strings.Contains(scheme, "=") {
+		return scheme + " <redacted>"
+	}
+	return "<redacted>"
+}
+
+func redactBody(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	body := secretJSONField.ReplaceAllString(string(data), `$1"<redacted>"`)
+	return secretFormField.ReplaceAllString(body, `$1$2<redacted>`)
+}
+
+func writeHeaders(b *strings.Builder, prefix string, h map[string][]string) {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, v := range h[key] {
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, key, v)
+		}
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + fmt.Sprintf("... (%d bytes truncated)", len(s)-n)
+}
+
+// OpenTraceFile creates a recorder writing to path: a HAR 1.2 archive if the
+// name ends in ".har" (written on Close), JSON lines (one TraceEntry per
+// line, written as requests complete) otherwise.
+func OpenTraceFile(path string) (TraceRecorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".har") {
+		return &harRecorder{file: f}, nil
+	}
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	return &jsonLinesRecorder{file: f, enc: enc}, nil
+}
+
+type jsonLinesRecorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+func (r *jsonLinesRecorder) Record(e *TraceEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(e)
+}
+
+func (r *jsonLinesRecorder) Close() error {
+	return r.file.Close()
+}
+
+// harRecorder collects entries and writes the whole archive on Close,
+// since HAR is a single JSON document.
+type harRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries []*TraceEntry
+}
+
+func (r *harRecorder) Record(e *TraceEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *harRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	toPairs := func(h map[string][]string) []nameValue {
+		pairs := []nameValue{}
+		keys := make([]string, 0, len(h))
+		for key := range h {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, v := range h[key] {
+				pairs = append(pairs, nameValue{key, v})
+			}
+		}
+		return pairs
+	}
+	firstValue := func(h map[string][]string, key string) string {
+		if v := h[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	entries := []interface{}{}
+	for _, e := range r.entries {
+		request := map[string]interface{}{
+			"method":      e.Method,
+			"url":         e.URL,
+			"httpVersion": "HTTP/1.1",
+			"headers":     toPairs(e.RequestHeaders),
+			"queryString": queryPairs(e.URL),
+			"cookies":     []nameValue{},
+			"headersSize": -1,
+			"bodySize":    len(e.RequestBody),
+		}
+		if e.RequestBody != "" {
+			request["postData"] = map[string]string{
+				"mimeType": firstValue(e.RequestHeaders, "Content-Type"),
+				"text":     e.RequestBody,
+			}
+		}
+		response := map[string]interface{}{
+			"status":      e.Status,
+			"statusText":  e.StatusText,
+			"httpVersion": e.Proto,
+			"headers":     toPairs(e.ResponseHeaders),
+			"cookies":     []nameValue{},
+			"content": map[string]interface{}{
+				"size":     e.ResponseSize,
+				"mimeType": firstValue(e.ResponseHeaders, "Content-Type"),
+				"text":     e.ResponseBody,
+			},
+			"redirectURL": "",
+			"headersSize": -1,
+			"bodySize":    e.ResponseSize,
+		}
+		entry := map[string]interface{}{
+			"startedDateTime": e.Time.Format(time.RFC3339Nano),
+			"time":            e.DurationMs,
+			"request":         request,
+			"response":        response,
+			"cache":           map[string]interface{}{},
+			"timings":         map[string]int64{"send": 0, "wait": e.DurationMs, "receive": 0},
+		}
+		if e.Error != "" {
+			entry["_error"] = e.Error
+		}
+		entries = append(entries, entry)
+	}
+
+	har := map[string]interface{}{
+		"log": map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]string{"name": "src", "version": "dev"},
+			"entries": entries,
+		},
+	}
+	enc := json.NewEncoder(r.file)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(har); err != nil {
+		r.file.Close()
+		return fmt.Errorf("failed to write HAR trace file: %w", err)
+	}
+	return r.file.Close()
+}
+
+type nameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func queryPairs(rawURL string) []nameValue {
+	pairs := []nameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return pairs
+	}
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, v := range query[key] {
+			pairs = append(pairs, nameValue{key, v})
+		}
+	}
+	return pairs
+}
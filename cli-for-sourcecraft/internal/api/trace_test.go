@@ -0,0 +1,126 @@
+// internal/api/trace_test.go
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Секреты, которые не должны попасть ни в журнал, ни в файл трассировки.
+var traceSecrets = []string{
+	"bearer-secret", "cookie-secret", "set-cookie-secret",
+	"body-access-secret", "body-refresh-secret", "form-refresh-secret", "form-client-secret",
+	"reply-access-secret", "reply-refresh-secret", "reply-device-secret",
+}
+
+// traceRequests sends a JSON request and a form-encoded OAuth-style request
+// through a TracingTransport writing to recorder and to the returned log.
+func traceRequests(t *testing.T, recorder TraceRecorder) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=set-cookie-secret; HttpOnly")
+		w.Write([]byte(`{"access_token": "reply-access-secret", "refresh_token":"reply-refresh-secret", "device_code": "reply-device-secret", "user_code": "ABCD-EFGH"}`))
+	}))
+	defer srv.Close()
+
+	var log bytes.Buffer
+	client := &http.Client{Transport: &TracingTransport{Base: srv.Client().Transport, Log: &log, LogBodies: true, Recorder: recorder}}
+
+	jsonReq, _ := http.NewRequest(http.MethodPost, srv.URL+"/me?page_size=1", strings.NewReader(`{"name": "n", "access_token": "body-access-secret", "refresh_token": "body-refresh-secret"}`))
+	jsonReq.Header.Set("Authorization", "Bearer bearer-secret")
+	jsonReq.Header.Set("Cookie", "session=cookie-secret")
+	jsonReq.Header.Set("Content-Type", "application/json")
+
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"form-refresh-secret"}, "client_secret": {"form-client-secret"}, "client_id": {"src"}}
+	formReq, _ := http.NewRequest(http.MethodPost, srv.URL+"/oauth/token", strings.NewReader(form.Encode()))
+	formReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	for _, req := range []*http.Request{jsonReq, formReq} {
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return log.String()
+}
+
+func assertNoSecrets(t *testing.T, name, text string) {
+	t.Helper()
+	for _, secret := range traceSecrets {
+		if strings.Contains(text, secret) {
+			t.Errorf("%s contains %q:\n%s", name, secret, text)
+		}
+	}
+	if !strings.Contains(text, "<redacted>") {
+		t.Errorf("%s has no <redacted> markers:\n%s", name, text)
+	}
+}
+
+func TestTraceRedactsSecrets(t *testing.T) {
+	for _, name := range []string{"trace.jsonl", "trace.har"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			recorder, err := OpenTraceFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			log := traceRequests(t, recorder)
+			assertNoSecrets(t, "stderr log", log)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertNoSecrets(t, name, string(data))
+			// Остальное содержимое сохраняется
+			for _, keep := range []string{"Bearer <redacted>", "ABCD-EFGH", "client_id=src", `\"name\": \"n\"`} {
+				if !strings.Contains(string(data), keep) {
+					t.Errorf("%s does not contain %s", name, keep)
+				}
+			}
+			if strings.HasSuffix(name, ".har") {
+				var har struct {
+					Log struct {
+						Entries []json.RawMessage `json:"entries"`
+					} `json:"log"`
+				}
+				if err := json.Unmarshal(data, &har); err != nil || len(har.Log.Entries) != 2 {
+					t.Errorf("HAR has %d entries (error %v), want 2", len(har.Log.Entries), err)
+				}
+			} else if lines := strings.Count(string(data), "\n"); lines != 2 {
+				t.Errorf("JSON lines file has %d lines, want 2", lines)
+			}
+		})
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{"token":"abc"}`, `{"token":"<redacted>"}`},
+		{`{"tokens": 3, "password" : "p"}`, `{"tokens": 3, "password" : "<redacted>"}`},
+		{`grant_type=device_code&device_code=abc&client_id=x`, `grant_type=device_code&device_code=<redacted>&client_id=x`},
+		{`refresh_token=abc`, `refresh_token=<redacted>`},
+		{`my_token=abc&x=1`, `my_token=abc&x=1`},
+		{`plain text`, `plain text`},
+	}
+	for _, tt := range tests {
+		if got := redactBody([]byte(tt.in)); got != tt.want {
+			t.Errorf("redactBody(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
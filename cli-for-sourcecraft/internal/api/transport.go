@@ -0,0 +1,92 @@
+// internal/api/transport.go
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TransportConfig — сетевые настройки HTTP-клиента: прокси, доверенные CA и клиентский сертификат.
+type TransportConfig struct {
+	// Proxy is the proxy URL, e.g. http://proxy.corp:3128. Empty means
+	// HTTPS_PROXY/HTTP_PROXY/NO_PROXY from the environment; "direct" disables
+	// proxying even if those are set.
+	Proxy string
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+	// ClientCert and ClientKey are PEM files for mutual TLS. Both or neither.
+	ClientCert string
+	ClientKey  string
+	// InsecureSkipVerify disables server certificate checks. For lab setups only.
+	InsecureSkipVerify bool
+}
+
+// NewTransport builds the http.Transport used by NewClient. It only bounds
+// connection setup and waiting for headers: overall deadlines come from the
+// request context.
+func NewTransport(cfg TransportConfig) (*http.Transport, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file '%s': %w", cfg.CAFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file '%s' contains no PEM certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return nil, fmt.Errorf("both 'client_cert' and 'client_key' must be set for mutual TLS")
+	}
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate '%s' / key '%s': %w", cfg.ClientCert, cfg.ClientKey, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	switch p := strings.TrimSpace(cfg.Proxy); {
+	case p == "":
+	case strings.EqualFold(p, "direct"):
+		proxy = nil
+	default:
+		if !strings.Contains(p, "://") {
+			p = "http://" + p
+		}
+		proxyURL, err := url.Parse(p)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL '%s'", cfg.Proxy)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}, nil
+}
@@ -0,0 +1,252 @@
+// internal/credstore/file.go
+package credstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	fileVersion       = 1
+	fileKDF           = "pbkdf2-sha256"
+	fileKDFIterations = 600_000
+	keySourcePhrase   = "passphrase"
+	keySourceMachine  = "machine"
+)
+
+// fileAAD binds the ciphertext to this file format.
+var fileAAD = []byte("sourcecraft-cli credentials v1")
+
+// FileStore keeps secrets in a single file encrypted with AES-256-GCM.
+//
+// The key is derived with PBKDF2-SHA256 from Passphrase or, if it is empty,
+// from the machine ID and the current user. A machine key only protects the
+// file from being read on another computer; use a passphrase for anything
+// stronger.
+type FileStore struct {
+	Path       string
+	Passphrase string
+
+	// Ключ выводится один раз за процесс: PBKDF2 намеренно медленный
+	key       []byte
+	keySalt   []byte
+	keySource string
+}
+
+// fileEnvelope is the on-disk format. Only Data is secret.
+type fileEnvelope struct {
+	Version    int    `json:"version"`
+	KeySource  string `json:"key_source"` // "passphrase" or "machine"
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func (s *FileStore) Get(key string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (s *FileStore) Set(key, secret string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[key] = secret
+	return s.save(secrets)
+}
+
+func (s *FileStore) Delete(key string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets, key)
+	return s.save(secrets)
+}
+
+func (s *FileStore) Description() string {
+	return "encrypted file " + s.Path
+}
+
+func (s *FileStore) load() (map[string]string, error) {
+	secrets := map[string]string{}
+	raw, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var env fileEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("credentials file '%s' is corrupted: %w", s.Path, err)
+	}
+	if env.Version != fileVersion || env.KDF != fileKDF || env.Iterations <= 0 {
+		return nil, fmt.Errorf("credentials file '%s' has an unsupported format (version %d, kdf %q)", s.Path, env.Version, env.KDF)
+	}
+
+	key, err := s.deriveKey(env.KeySource, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, env.Nonce, env.Data, fileAAD)
+	if err != nil {
+		if env.KeySource == keySourcePhrase {
+			return nil, fmt.Errorf("cannot decrypt credentials file '%s': wrong passphrase in $%s", s.Path, PassphraseEnvVar)
+		}
+		return nil, fmt.Errorf("cannot decrypt credentials file '%s': it was created on another machine or by another user", s.Path)
+	}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("credentials file '%s' is corrupted: %w", s.Path, err)
+	}
+	return secrets, nil
+}
+
+// save encrypts secrets with a fresh nonce and replaces the file atomically.
+func (s *FileStore) save(secrets map[string]string) error {
+	source := keySourceMachine
+	if s.Passphrase != "" {
+		source = keySourcePhrase
+	}
+	salt := s.keySalt
+	if s.key == nil || s.keySource != source {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	key, err := s.deriveKey(source, salt, fileKDFIterations)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(fileEnvelope{
+		Version:    fileVersion,
+		KeySource:  source,
+		KDF:        fileKDF,
+		Iterations: fileKDFIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, plain, fileAAD),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for credentials file: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	_, writeErr := tmp.Write(append(data, '\n'))
+	closeErr := tmp.Close()
+	if writeErr == nil && closeErr == nil {
+		writeErr = os.Chmod(tmp.Name(), 0600)
+	}
+	if writeErr == nil && closeErr == nil {
+		writeErr = os.Rename(tmp.Name(), s.Path)
+	}
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write credentials file: %w", errors.Join(writeErr, closeErr))
+	}
+	return nil
+}
+
+// deriveKey returns the AES key for source and salt, reusing the cached one if possible.
+func (s *FileStore) deriveKey(source string, salt []byte, iterations int) ([]byte, error) {
+	if s.key != nil && s.keySource == source && string(s.keySalt) == string(salt) {
+		return s.key, nil
+	}
+
+	var secret string
+	switch source {
+	case keySourcePhrase:
+		if s.Passphrase == "" {
+			return nil, fmt.Errorf("credentials file '%s' is protected by a passphrase: set $%s", s.Path, PassphraseEnvVar)
+		}
+		secret = s.Passphrase
+	case keySourceMachine:
+		secret = machineSecret()
+	default:
+		return nil, fmt.Errorf("credentials file '%s' has an unknown key source %q", s.Path, source)
+	}
+
+	key, err := pbkdf2.Key(sha256.New, secret, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("key derivation failed: %w", err)
+	}
+	s.key, s.keySalt, s.keySource = key, salt, source
+	return key, nil
+}
+
+// machineSecret identifies this machine and user: the systemd/D-Bus machine
+// ID (or the hostname where there is none), the user name and home directory.
+func machineSecret() string {
+	id := ""
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(path); err == nil {
+			id = strings.TrimSpace(string(data))
+			break
+		}
+	}
+	if id == "" {
+		id, _ = os.Hostname()
+	}
+	name := ""
+	if u, err := user.Current(); err == nil {
+		name = u.Username + "\n" + u.HomeDir
+	}
+	return "sourcecraft-cli\n" + id + "\n" + name
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
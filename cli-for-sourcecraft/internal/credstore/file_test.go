@@ -0,0 +1,207 @@
+// internal/credstore/file_test.go
+package credstore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func newTestFileStore(t *testing.T, passphrase string) *FileStore {
+	t.Helper()
+	return &FileStore{Path: filepath.Join(t.TempDir(), "credentials.enc"), Passphrase: passphrase}
+}
+
+// readEnvelope returns the envelope of the file at path.
+func readEnvelope(t *testing.T, path string) fileEnvelope {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env fileEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		t.Fatalf("credentials file is not JSON: %v", err)
+	}
+	return env
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		s := newTestFileStore(t, passphrase)
+		if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get() on a missing file: error = %v, want ErrNotFound", err)
+		}
+		if err := s.Set("host:alice", "token-a"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if err := s.Set("host:bob", "token-b"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+
+		// Новый экземпляр читает файл с диска, без кешированного ключа
+		fresh := &FileStore{Path: s.Path, Passphrase: passphrase}
+		if got, err := fresh.Get("host:alice"); err != nil || got != "token-a" {
+			t.Errorf("Get(alice) = %q, %v; want token-a", got, err)
+		}
+		if err := fresh.Delete("host:alice"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := fresh.Delete("host:alice"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+		}
+		if _, err := s.Get("host:alice"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(alice) after Delete: error = %v, want ErrNotFound", err)
+		}
+		if got, err := s.Get("host:bob"); err != nil || got != "token-b" {
+			t.Errorf("Get(bob) = %q, %v; want token-b", got, err)
+		}
+
+		raw, _ := os.ReadFile(s.Path)
+		if strings.Contains(string(raw), "token-b") {
+			t.Errorf("credentials file contains the token in plain text:\n%s", raw)
+		}
+		matches, _ := filepath.Glob(s.Path + ".*.tmp")
+		if len(matches) != 0 {
+			t.Errorf("temporary files left behind: %v", matches)
+		}
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	s := newTestFileStore(t, "right")
+	if err := s.Set("k", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(s.Path)
+
+	wrong := &FileStore{Path: s.Path, Passphrase: "wrong"}
+	if _, err := wrong.Get("k"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Get() with a wrong passphrase: error = %v, want the wrong passphrase error", err)
+	}
+	if err := wrong.Set("k", "other"); err == nil {
+		t.Error("Set() with a wrong passphrase: want an error")
+	}
+
+	none := &FileStore{Path: s.Path}
+	if _, err := none.Get("k"); err == nil || !strings.Contains(err.Error(), PassphraseEnvVar) {
+		t.Errorf("Get() without a passphrase: error = %v, want a hint to set %s", err, PassphraseEnvVar)
+	}
+	// Без пароля файл не перешифровывается машинным ключом
+	if err := none.Set("k", "other"); err == nil {
+		t.Error("Set() without the passphrase: want an error")
+	}
+	if after, _ := os.ReadFile(s.Path); string(after) != string(before) {
+		t.Error("the file was rewritten by a store that could not decrypt it")
+	}
+}
+
+func TestFileStoreReencryptsWhenKeySourceChanges(t *testing.T) {
+	s := newTestFileStore(t, "")
+	if err := s.Set("k", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	machine := readEnvelope(t, s.Path)
+	if machine.KeySource != keySourceMachine {
+		t.Fatalf("key source = %q, want %q", machine.KeySource, keySourceMachine)
+	}
+
+	// machine → passphrase: файл читается машинным ключом, сохраняется с паролем
+	withPhrase := &FileStore{Path: s.Path, Passphrase: "pw"}
+	if err := withPhrase.Set("k2", "secret2"); err != nil {
+		t.Fatalf("Set() with a passphrase over a machine-key file: %v", err)
+	}
+	phrase := readEnvelope(t, s.Path)
+	if phrase.KeySource != keySourcePhrase || string(phrase.Salt) == string(machine.Salt) {
+		t.Errorf("after machine → passphrase: key source %q, salt reused %v; want %q with a new salt",
+			phrase.KeySource, string(phrase.Salt) == string(machine.Salt), keySourcePhrase)
+	}
+	if _, err := (&FileStore{Path: s.Path}).Get("k"); err == nil {
+		t.Error("the file is still readable with the machine key")
+	}
+	if got, err := (&FileStore{Path: s.Path, Passphrase: "pw"}).Get("k"); err != nil || got != "secret" {
+		t.Errorf("Get(k) with the passphrase = %q, %v; want the secret kept", got, err)
+	}
+
+	// passphrase → machine: пароль убран у открытого хранилища, ключ уже выведен
+	withPhrase.Passphrase = ""
+	if err := withPhrase.Set("k3", "secret3"); err != nil {
+		t.Fatalf("Set() after clearing the passphrase: %v", err)
+	}
+	if env := readEnvelope(t, s.Path); env.KeySource != keySourceMachine || string(env.Salt) == string(phrase.Salt) {
+		t.Errorf("after passphrase → machine: key source %q; want %q with a new salt", env.KeySource, keySourceMachine)
+	}
+	fresh := &FileStore{Path: s.Path}
+	for key, want := range map[string]string{"k": "secret", "k2": "secret2", "k3": "secret3"} {
+		if got, err := fresh.Get(key); err != nil || got != want {
+			t.Errorf("Get(%s) with the machine key = %q, %v; want %q", key, got, err, want)
+		}
+	}
+}
+
+func TestFileStoreBadEnvelope(t *testing.T) {
+	s := newTestFileStore(t, "pw")
+	if err := s.Set("k", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	good := readEnvelope(t, s.Path)
+
+	tests := []struct {
+		name    string
+		modify  func(env *fileEnvelope)
+		raw     string // вместо конверта
+		wantErr string
+	}{
+		{name: "not JSON", raw: "garbage", wantErr: "corrupted"},
+		{name: "newer version", modify: func(env *fileEnvelope) { env.Version = 2 }, wantErr: "unsupported format"},
+		{name: "other KDF", modify: func(env *fileEnvelope) { env.KDF = "scrypt" }, wantErr: "unsupported format"},
+		{name: "no iterations", modify: func(env *fileEnvelope) { env.Iterations = 0 }, wantErr: "unsupported format"},
+		{name: "unknown key source", modify: func(env *fileEnvelope) { env.KeySource = "tpm" }, wantErr: "unknown key source"},
+		{name: "flipped ciphertext bit", modify: func(env *fileEnvelope) { env.Data[0] ^= 1 }, wantErr: "cannot decrypt"},
+		{name: "other nonce", modify: func(env *fileEnvelope) { env.Nonce[0] ^= 1 }, wantErr: "cannot decrypt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := []byte(tt.raw)
+			if tt.modify != nil {
+				env := good
+				env.Data = append([]byte(nil), good.Data...)
+				env.Nonce = append([]byte(nil), good.Nonce...)
+				tt.modify(&env)
+				raw, _ = json.Marshal(env)
+			}
+			if err := os.WriteFile(s.Path, raw, 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := (&FileStore{Path: s.Path, Passphrase: "pw"}).Get("k")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Get() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileStoreFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix file modes")
+	}
+	s := &FileStore{Path: filepath.Join(t.TempDir(), "new", "credentials.enc")}
+	if err := s.Set("k", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("file mode = %v, want 0600", mode)
+	}
+	dir, _ := os.Stat(filepath.Dir(s.Path))
+	if mode := dir.Mode().Perm(); mode&0077 != 0 {
+		t.Errorf("directory mode = %v, want no access for group and others", mode)
+	}
+}
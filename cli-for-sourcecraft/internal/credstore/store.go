@@ -0,0 +1,117 @@
+// internal/credstore/store.go
+package credstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Виды хранилищ для ключа credential_store в config.yaml.
+const (
+	KindKeyring = "keyring" // OS keyring (Keychain, Credential Manager, Secret Service)
+	KindFile    = "file"    // Encrypted file, see FileStore
+	KindEnv     = "env"     // Nothing is stored, tokens come from the environment only
+)
+
+// PassphraseEnvVar holds the passphrase of the encrypted file. Without it a
+// machine key is used.
+const PassphraseEnvVar = "SRC_CREDENTIALS_PASSPHRASE"
+
+// ErrNotFound is returned by Get and Delete when there is no secret for the key.
+var ErrNotFound = errors.New("secret not found")
+
+// ErrReadOnly is returned by Set of a store that cannot save secrets.
+var ErrReadOnly = errors.New("credential store is read-only")
+
+// Store keeps secrets (tokens) by key.
+type Store interface {
+	Get(key string) (string, error)
+	Set(key, secret string) error
+	Delete(key string) error
+	// Description is a human-readable name for messages, e.g. "OS keyring".
+	Description() string
+}
+
+// KeyringStore keeps secrets in the OS keyring under Service.
+type KeyringStore struct {
+	Service string
+}
+
+func (s *KeyringStore) Get(key string) (string, error) {
+	secret, err := keyring.Get(s.Service, key)
+	if err == keyring.ErrNotFound {
+		return "", ErrNotFound
+	}
+	return secret, err
+}
+
+func (s *KeyringStore) Set(key, secret string) error {
+	return keyring.Set(s.Service, key, secret)
+}
+
+func (s *KeyringStore) Delete(key string) error {
+	err := keyring.Delete(s.Service, key)
+	if err == keyring.ErrNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *KeyringStore) Description() string {
+	return "OS keyring"
+}
+
+// Available reports whether the OS keyring can be used at all. It fails,
+// for example, in containers without a Secret Service on D-Bus.
+func (s *KeyringStore) Available() bool {
+	_, err := keyring.Get(s.Service, "availability-check")
+	return err == nil || err == keyring.ErrNotFound
+}
+
+// EnvStore saves nothing: tokens must come from the environment
+// (SOURCECRAFT_TOKEN or SOURCECRAFT_ENTERPRISE_TOKEN).
+type EnvStore struct{}
+
+func (EnvStore) Get(key string) (string, error) { return "", ErrNotFound }
+func (EnvStore) Set(key, secret string) error   { return ErrReadOnly }
+func (EnvStore) Delete(key string) error        { return ErrNotFound }
+func (EnvStore) Description() string            { return "environment (credential_store: env)" }
+
+// machineKeyWarning is printed once per process when Open falls back to a
+// file protected by the machine key.
+var machineKeyWarning sync.Once
+
+// Open returns the store of the given kind. An empty kind means the OS keyring,
+// or the encrypted file at filePath if the keyring is not available.
+// The file is protected by $SRC_CREDENTIALS_PASSPHRASE or a machine key; the
+// fallback to a machine key is reported on stderr, because anyone who can read
+// /etc/machine-id and knows the user name can derive that key.
+func Open(kind, service, filePath string) (Store, error) {
+	passphrase := os.Getenv(PassphraseEnvVar)
+	switch kind {
+	case KindKeyring:
+		return &KeyringStore{Service: service}, nil
+	case KindFile:
+		return &FileStore{Path: filePath, Passphrase: passphrase}, nil
+	case KindEnv:
+		return EnvStore{}, nil
+	case "":
+		kr := &KeyringStore{Service: service}
+		if kr.Available() {
+			return kr, nil
+		}
+		if passphrase == "" {
+			machineKeyWarning.Do(func() {
+				fmt.Fprintf(os.Stderr, "Warning: the OS keyring is not available, so tokens are kept in %s encrypted with a machine key, "+
+					"which anyone who can read /etc/machine-id and knows your user name can derive. "+
+					"Set $%s, or choose 'credential_store' in config.yaml explicitly.\n", filePath, PassphraseEnvVar)
+			})
+		}
+		return &FileStore{Path: filePath, Passphrase: passphrase}, nil
+	}
+	return nil, fmt.Errorf("unknown credential_store '%s'. Use one of: %s, %s, %s", kind, KindKeyring, KindFile, KindEnv)
+}
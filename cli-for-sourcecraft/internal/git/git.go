@@ -5,7 +5,9 @@ import (
 	"cli-for-sourcecraft/internal/api"
 	"fmt"
 	"net/url"
+	"os"
 	"os/exec"
+	"sort"
 	"strings"
 )
 
@@ -162,7 +164,7 @@ func GetDefaultBranchName(apiRepoInfo *api.Repo, remoteName string) (string, err
 	if err != nil {
 		// Если repoInfo не было и remote не найден - не страшно, вернем дефолт
 		if apiRepoInfo == nil && strings.Contains(err.Error(), "not found") {
-			fmt.Println("Warning: Could not determine default branch via git remote show (remote not found?). Assuming 'main'.")
+			fmt.Fprintln(os.Stderr, "Warning: Could not determine default branch via git remote show (remote not found?). Assuming 'main'.")
 			return "main", nil // Возвращаем дефолт, а не ошибку
 		}
 		stderr := ""
@@ -188,7 +190,7 @@ func GetDefaultBranchName(apiRepoInfo *api.Repo, remoteName string) (string, err
 	}
 
 	// 3. Финальный дефолт
-	fmt.Println("Warning: Could not determine default branch via git remote show. Assuming 'main'.")
+	fmt.Fprintln(os.Stderr, "Warning: Could not determine default branch via git remote show. Assuming 'main'.")
 	return "main", nil
 }
 
@@ -218,8 +220,131 @@ func GetCommitMessagesSinceBase(baseBranch, headBranch string) (string, error) {
 			stderr = string(exitErr.Stderr)
 		}
 		// Не фатально, можем просто не предлагать тело PR
-		fmt.Printf("Warning: failed to get commit messages for range '%s': %v. Stderr: %s\n", rangeSpec, err, stderr)
+		fmt.Fprintf(os.Stderr, "Warning: failed to get commit messages for range '%s': %v. Stderr: %s\n", rangeSpec, err, stderr)
 		return "", nil // Возвращаем пустую строку, а не ошибку
 	}
 	return strings.TrimSpace(string(output)), nil // Возвращаем все заголовки
 }
+
+// BranchExists сообщает, есть ли локальная ветка с таким именем.
+func BranchExists(branchName string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branchName)
+	return cmd.Run() == nil
+}
+
+// IsAncestor сообщает, содержится ли коммит ancestor в истории descendant
+// (git merge-base --is-ancestor). Несуществующий ref даёт false.
+func IsAncestor(ancestor, descendant string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	return cmd.Run() == nil
+}
+
+// GetShortCommitHash возвращает сокращённый хэш коммита, на который указывает ref.
+func GetShortCommitHash(refName string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--short", refName)
+	output, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		return "", fmt.Errorf("failed to resolve '%s': %w. Stderr: %s", refName, err, stderr)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetChangedFiles возвращает файлы, изменённые в headBranch с момента
+// расхождения с baseBranch (git diff --name-only base...head). Если локальной
+// base нет, используется origin/<base>.
+func GetChangedFiles(baseBranch, headBranch string) ([]string, error) {
+	var lastErr error
+	for _, base := range []string{"origin/" + baseBranch, baseBranch} {
+		cmd := exec.Command("git", "diff", "--name-only", base+"..."+headBranch)
+		output, err := cmd.Output()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var files []string
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+		return files, nil
+	}
+	return nil, fmt.Errorf("failed to list files changed between '%s' and '%s': %w", baseBranch, headBranch, lastErr)
+}
+
+// HasUncommittedChanges сообщает, есть ли в рабочей копии незакоммиченные
+// изменения отслеживаемых файлов (неотслеживаемые файлы не учитываются).
+func HasUncommittedChanges() (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to run 'git status': %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// FindRemoteForRepo возвращает имя remote, указывающего на owner/repo
+// ("" если такого нет). Предпочитаются 'origin' и 'upstream'.
+func FindRemoteForRepo(owner, repo string) (string, error) {
+	remotes, err := GetRemotes()
+	if err != nil {
+		return "", err
+	}
+	matches := func(name string) bool {
+		remoteURL, ok := remotes[name]
+		if !ok {
+			return false
+		}
+		o, r, err := ParseOwnerAndRepoFromURL(remoteURL)
+		return err == nil && strings.EqualFold(o, owner) && strings.EqualFold(r, repo)
+	}
+	for _, name := range []string{"origin", "upstream"} {
+		if matches(name) {
+			return name, nil
+		}
+	}
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if matches(name) {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// GetCommitHash возвращает полный хэш коммита, на который указывает ref.
+func GetCommitHash(refName string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", refName+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		return "", fmt.Errorf("failed to resolve '%s': %w. Stderr: %s", refName, err, stderr)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetDiff возвращает unified diff изменений head с момента расхождения с base
+// (git diff base...head), без цветов.
+func GetDiff(base, head string) (string, error) {
+	cmd := exec.Command("git", "diff", "--no-color", "--no-ext-diff", base+"..."+head)
+	output, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		return "", fmt.Errorf("failed to run 'git diff %s...%s': %w. Stderr: %s", base, head, err, stderr)
+	}
+	return string(output), nil
+}
@@ -0,0 +1,206 @@
+// internal/oauth/device.go
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Ошибки завершения device flow (RFC 8628, раздел 3.5).
+var (
+	ErrAccessDenied = errors.New("authorization was denied")
+	ErrExpiredToken = errors.New("device code expired")
+)
+
+// pollUnit is the unit of interval and expires_in in PollToken: seconds per
+// RFC 8628. Tests shorten it to keep the polling fast.
+var pollUnit = time.Second
+
+// Config describes an OAuth 2.0 authorization server and the client using it.
+type Config struct {
+	ClientID      string
+	DeviceAuthURL string // Device authorization endpoint (RFC 8628)
+	TokenURL      string // Token endpoint
+	Scopes        []string
+	HTTPClient    *http.Client // nil = http.DefaultClient
+}
+
+// DeviceCode is the device authorization response.
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"` // Seconds
+	Interval                int    `json:"interval"`   // Seconds between polls, 5 if absent
+}
+
+// Token is a token endpoint response. Expiry is computed from ExpiresIn.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresIn    int       `json:"expires_in,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	Expiry       time.Time `json:"-"`
+}
+
+// Error is an error response of the authorization server (RFC 6749, 5.2).
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	StatusCode  int    `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("OAuth error: %s (%s)", e.Code, e.Description)
+	}
+	return fmt.Sprintf("OAuth error: %s (HTTP %d)", e.Code, e.StatusCode)
+}
+
+// RequestDeviceCode starts the device flow: the user has to open
+// VerificationURI and enter UserCode.
+func (c *Config) RequestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{"client_id": {c.ClientID}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	var dc DeviceCode
+	if err := c.post(ctx, c.DeviceAuthURL, form, &dc); err != nil {
+		return nil, err
+	}
+	if dc.DeviceCode == "" || dc.UserCode == "" || dc.VerificationURI == "" {
+		return nil, fmt.Errorf("device authorization response from %s is incomplete", c.DeviceAuthURL)
+	}
+	if dc.Interval <= 0 {
+		dc.Interval = 5
+	}
+	return &dc, nil
+}
+
+// PollToken polls the token endpoint until the user approves or denies the
+// request, the device code expires or ctx is cancelled.
+func (c *Config) PollToken(ctx context.Context, dc *DeviceCode) (*Token, error) {
+	interval := time.Duration(dc.Interval) * pollUnit
+	var deadline <-chan time.Time
+	if dc.ExpiresIn > 0 {
+		timer := time.NewTimer(time.Duration(dc.ExpiresIn) * pollUnit)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {dc.DeviceCode},
+		"client_id":   {c.ClientID},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, ErrExpiredToken
+		case <-time.After(interval):
+		}
+
+		tok, err := c.token(ctx, form)
+		var oauthErr *Error
+		if !errors.As(err, &oauthErr) {
+			return tok, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * pollUnit
+		case "access_denied":
+			return nil, ErrAccessDenied
+		case "expired_token":
+			return nil, ErrExpiredToken
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Refresh exchanges a refresh token for a new access token. The server may
+// rotate the refresh token; if it does not, the old one is kept in the result.
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	tok, err := c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.ClientID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+func (c *Config) token(ctx context.Context, form url.Values) (*Token, error) {
+	var tok Token
+	if err := c.post(ctx, c.TokenURL, form, &tok); err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("token response from %s has no access_token", c.TokenURL)
+	}
+	if tok.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	return &tok, nil
+}
+
+// post sends a form and decodes a JSON response into v, or returns *Error.
+func (c *Config) post(ctx context.Context, endpoint string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("request creation error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", endpoint, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		oauthErr := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(body, oauthErr) == nil && oauthErr.Code != "" {
+			return oauthErr
+		}
+		snippet := string(body)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return fmt.Errorf("POST %s returned %s. Response start: %s", endpoint, resp.Status, snippet)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		snippet := string(body)
+		if len(snippet) > 150 {
+			snippet = snippet[:150] + "..."
+		}
+		return fmt.Errorf("failed to decode JSON from POST %s: %w. Response start: %s", endpoint, err, snippet)
+	}
+	return nil
+}
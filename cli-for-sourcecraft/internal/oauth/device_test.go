@@ -0,0 +1,263 @@
+// internal/oauth/device_test.go
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"cli-for-sourcecraft/internal/api"
+)
+
+// fakeAuthServer is a local authorization server. The device code grant
+// answers with pollReplies in order (an OAuth error code, or "" for a
+// token); the refresh token grant rotates the refresh token.
+type fakeAuthServer struct {
+	t           *testing.T
+	pollReplies []string
+
+	mu        sync.Mutex
+	polls     []time.Time
+	refreshes []string // refresh_token каждого запроса на обновление
+}
+
+func (s *fakeAuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.t.Errorf("bad form: %v", err)
+	}
+	if r.Form.Get("client_id") != "test-client" {
+		s.t.Errorf("%s: client_id = %q, want test-client", r.URL.Path, r.Form.Get("client_id"))
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.URL.Path == "/oauth/device/code":
+		json.NewEncoder(w).Encode(DeviceCode{
+			DeviceCode:      "dev-123",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: "https://example.test/device",
+			ExpiresIn:       600,
+			Interval:        1,
+		})
+	case r.URL.Path == "/oauth/token" && r.Form.Get("grant_type") == "urn:ietf:params:oauth:grant-type:device_code":
+		if r.Form.Get("device_code") != "dev-123" {
+			s.t.Errorf("device_code = %q, want dev-123", r.Form.Get("device_code"))
+		}
+		s.polls = append(s.polls, time.Now())
+		reply := ""
+		if len(s.polls) <= len(s.pollReplies) {
+			reply = s.pollReplies[len(s.polls)-1]
+		}
+		if reply != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": reply})
+			return
+		}
+		json.NewEncoder(w).Encode(Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresIn: 3600})
+	case r.URL.Path == "/oauth/token" && r.Form.Get("grant_type") == "refresh_token":
+		s.refreshes = append(s.refreshes, r.Form.Get("refresh_token"))
+		if r.Form.Get("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(Token{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600})
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeAuth(t *testing.T, pollReplies ...string) (*fakeAuthServer, *Config) {
+	t.Helper()
+	// Интервалы опроса в миллисекундах вместо секунд
+	oldUnit := pollUnit
+	pollUnit = 10 * time.Millisecond
+	t.Cleanup(func() { pollUnit = oldUnit })
+
+	fake := &fakeAuthServer{t: t, pollReplies: pollReplies}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return fake, &Config{
+		ClientID:      "test-client",
+		DeviceAuthURL: srv.URL + "/oauth/device/code",
+		TokenURL:      srv.URL + "/oauth/token",
+		HTTPClient:    srv.Client(),
+	}
+}
+
+func TestDeviceFlow(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []string
+		wantErr error
+		polls   int
+	}{
+		{"approved at once", nil, nil, 1},
+		{"authorization_pending", []string{"authorization_pending", "authorization_pending"}, nil, 3},
+		{"access_denied", []string{"authorization_pending", "access_denied"}, ErrAccessDenied, 2},
+		{"expired_token", []string{"expired_token"}, ErrExpiredToken, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, cfg := newFakeAuth(t, tt.replies...)
+			dc, err := cfg.RequestDeviceCode(context.Background())
+			if err != nil {
+				t.Fatalf("RequestDeviceCode() error = %v", err)
+			}
+			if dc.UserCode != "ABCD-EFGH" {
+				t.Errorf("UserCode = %q, want ABCD-EFGH", dc.UserCode)
+			}
+
+			tok, err := cfg.PollToken(context.Background(), dc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PollToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if tok.AccessToken != "access-1" || tok.RefreshToken != "refresh-1" {
+					t.Errorf("token = %+v, want access-1/refresh-1", tok)
+				}
+				if time.Until(tok.Expiry) < 59*time.Minute {
+					t.Errorf("Expiry = %v, want about an hour from now", tok.Expiry)
+				}
+			}
+			if len(fake.polls) != tt.polls {
+				t.Errorf("token endpoint polled %d times, want %d", len(fake.polls), tt.polls)
+			}
+		})
+	}
+}
+
+func TestPollTokenSlowDown(t *testing.T) {
+	fake, cfg := newFakeAuth(t, "authorization_pending", "slow_down", "authorization_pending")
+	dc := &DeviceCode{DeviceCode: "dev-123", Interval: 1}
+
+	if _, err := cfg.PollToken(context.Background(), dc); err != nil {
+		t.Fatalf("PollToken() error = %v", err)
+	}
+	if len(fake.polls) != 4 {
+		t.Fatalf("token endpoint polled %d times, want 4", len(fake.polls))
+	}
+	// После slow_down интервал увеличивается на 5 единиц и остается таким
+	for i := 2; i < len(fake.polls); i++ {
+		if gap := fake.polls[i].Sub(fake.polls[i-1]); gap < 6*pollUnit {
+			t.Errorf("poll %d came %v after the previous one, want at least %v after slow_down", i+1, gap, 6*pollUnit)
+		}
+	}
+}
+
+func TestPollTokenDeviceCodeExpires(t *testing.T) {
+	pending := make([]string, 100)
+	for i := range pending {
+		pending[i] = "authorization_pending"
+	}
+	_, cfg := newFakeAuth(t, pending...)
+	dc := &DeviceCode{DeviceCode: "dev-123", Interval: 1, ExpiresIn: 5}
+
+	if _, err := cfg.PollToken(context.Background(), dc); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("PollToken() error = %v, want ErrExpiredToken", err)
+	}
+}
+
+func TestPollTokenCancelled(t *testing.T) {
+	_, cfg := newFakeAuth(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := cfg.PollToken(ctx, &DeviceCode{DeviceCode: "dev-123", Interval: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("PollToken() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRefreshKeepsRefreshTokenIfNotRotated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "access-2"}`))
+	}))
+	defer srv.Close()
+	cfg := &Config{ClientID: "test-client", TokenURL: srv.URL, HTTPClient: srv.Client()}
+
+	tok, err := cfg.Refresh(context.Background(), "refresh-1")
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if tok.AccessToken != "access-2" || tok.RefreshToken != "refresh-1" {
+		t.Errorf("token = %+v, want access-2 with the old refresh token", tok)
+	}
+}
+
+// TestClientRefreshesOnceOn401 wires Config.Refresh into api.Client the way
+// 'src' does and checks that an expired token is refreshed once and the
+// request is replayed with the new token.
+func TestClientRefreshesOnceOn401(t *testing.T) {
+	tests := []struct {
+		name      string
+		accepted  string // токен, который принимает API
+		wantErr   bool
+		apiCalls  int
+		refreshes int
+	}{
+		{"replayed with the new token", "access-2", false, 2, 1},
+		{"refreshed token also rejected", "none", true, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, cfg := newFakeAuth(t)
+
+			var apiCalls int
+			var bodies []string
+			apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				apiCalls++
+				var body struct{ Title string }
+				json.NewDecoder(r.Body).Decode(&body)
+				bodies = append(bodies, body.Title)
+				if r.Header.Get("Authorization") != "Bearer "+tt.accepted {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id": "1", "title": "t"}`))
+			}))
+			defer apiSrv.Close()
+
+			client := api.NewClient(apiSrv.URL, "access-1")
+			client.HTTPClient = apiSrv.Client()
+			refreshToken := "refresh-1"
+			client.Refresh = func(ctx context.Context) (string, error) {
+				tok, err := cfg.Refresh(ctx, refreshToken)
+				if err != nil {
+					return "", err
+				}
+				refreshToken = tok.RefreshToken
+				return tok.AccessToken, nil
+			}
+
+			// POST: повтор после 401 безопасен, сервер запрос не выполнял
+			_, err := client.CreateIssue(context.Background(), "o", "r", api.CreateIssueBody{Title: "t"})
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("CreateIssue() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, api.ErrUnauthorized) {
+				t.Errorf("CreateIssue() error = %v, want ErrUnauthorized", err)
+			}
+			if apiCalls != tt.apiCalls || len(fake.refreshes) != tt.refreshes {
+				t.Errorf("%d API calls and %d refreshes, want %d and %d", apiCalls, len(fake.refreshes), tt.apiCalls, tt.refreshes)
+			}
+			for i, title := range bodies {
+				if title != "t" {
+					t.Errorf("request %d has body title %q, want the body replayed", i+1, title)
+				}
+			}
+			if client.Token != "access-2" || refreshToken != "refresh-2" {
+				t.Errorf("client token %q, refresh token %q; want access-2 and the rotated refresh-2", client.Token, refreshToken)
+			}
+		})
+	}
+}
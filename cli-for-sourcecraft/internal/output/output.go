@@ -0,0 +1,293 @@
+// internal/output/output.go
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	cliutils "cli-for-sourcecraft/internal/utils"
+
+	"github.com/itchyny/gojq"
+)
+
+// AllFields is the --json value meaning "every field" (plain --json without a list).
+const AllFields = "*"
+
+// Options — параметры структурированного вывода (--json, --jq, --template).
+//
+// The value passed to Write is marshalled to JSON first, so field names are
+// always the JSON names of the API structs (e.g. "slug", "updated_at"), and
+// --jq and --template see exactly what --json would print.
+type Options struct {
+	JSON     string // Comma-separated field list, AllFields, or "" if --json was not given
+	JQ       string // jq expression
+	Template string // text/template source
+
+	query *gojq.Code
+	tmpl  *template.Template
+}
+
+// Enabled reports whether structured output was requested.
+func (o *Options) Enabled() bool {
+	return o.JSON != "" || o.JQ != "" || o.Template != ""
+}
+
+// Validate parses --jq and --template, so syntax errors are reported before
+// any API request is made.
+func (o *Options) Validate() error {
+	if o.JQ != "" && o.Template != "" {
+		return fmt.Errorf("--jq and --template cannot be used together")
+	}
+	if o.JQ != "" {
+		query, err := gojq.Parse(o.JQ)
+		if err != nil {
+			return fmt.Errorf("invalid --jq expression: %w", err)
+		}
+		code, err := gojq.Compile(query)
+		if err != nil {
+			return fmt.Errorf("invalid --jq expression: %w", err)
+		}
+		o.query = code
+	}
+	if o.Template != "" {
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(o.Template)
+		if err != nil {
+			return fmt.Errorf("invalid --template: %w", err)
+		}
+		o.tmpl = tmpl
+	}
+	return nil
+}
+
+// Write prints v (a struct, pointer to struct or slice of them) to w,
+// keeping only the fields requested with --json and then applying --jq or
+// --template, if given.
+func (o *Options) Write(w io.Writer, v interface{}) error {
+	if (o.JQ != "" && o.query == nil) || (o.Template != "" && o.tmpl == nil) {
+		if err := o.Validate(); err != nil {
+			return err
+		}
+	}
+
+	fields := o.fields()
+	if err := checkFields(v, fields); err != nil {
+		return err
+	}
+
+	data, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		data = filterFields(data, fields)
+	}
+
+	switch {
+	case o.query != nil:
+		return runJQ(w, o.query, data)
+	case o.tmpl != nil:
+		return o.tmpl.Execute(w, data)
+	}
+	return writeJSON(w, data, "  ")
+}
+
+// fields returns the --json field list, or nil for all fields.
+func (o *Options) fields() []string {
+	if o.JSON == "" || o.JSON == AllFields {
+		return nil
+	}
+	var fields []string
+	for _, f := range strings.Split(o.JSON, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// toGeneric round-trips v through JSON into maps and slices.
+func toGeneric(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("JSON marshal error: %w", err)
+	}
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("JSON decode error: %w", err)
+	}
+	return normalizeNumbers(data), nil
+}
+
+// normalizeNumbers converts json.Number to int or float64, which gojq understands.
+func normalizeNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, item := range x {
+			x[k] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range x {
+			x[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := x.Float64()
+		return f
+	}
+	return v
+}
+
+// filterFields keeps only fields in an object or in every object of an array.
+func filterFields(data interface{}, fields []string) interface{} {
+	switch x := data.(type) {
+	case map[string]interface{}:
+		filtered := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			if value, ok := x[f]; ok {
+				filtered[f] = value
+			} else {
+				filtered[f] = nil
+			}
+		}
+		return filtered
+	case []interface{}:
+		for i, item := range x {
+			x[i] = filterFields(item, fields)
+		}
+	}
+	return data
+}
+
+// checkFields returns an error listing the available fields if one of
+// fields is not a JSON field of v's struct type.
+func checkFields(v interface{}, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	available := FieldNames(v)
+	if len(available) == 0 {
+		return nil
+	}
+	known := make(map[string]bool, len(available))
+	for _, name := range available {
+		known[name] = true
+	}
+	for _, f := range fields {
+		if !known[f] {
+			return fmt.Errorf("unknown JSON field: %q\nAvailable fields:\n  %s", f, strings.Join(available, "\n  "))
+		}
+	}
+	return nil
+}
+
+// FieldNames returns the sorted JSON field names of v's struct type
+// (or of the element type, if v is a slice).
+func FieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runJQ prints every result of the query: strings as raw text (like jq -r),
+// everything else as compact JSON.
+func runJQ(w io.Writer, code *gojq.Code, data interface{}) error {
+	iter := code.Run(data)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, isErr := result.(error); isErr {
+			if haltErr, ok := err.(*gojq.HaltError); ok && haltErr.Value() == nil {
+				return nil
+			}
+			return fmt.Errorf("--jq: %w", err)
+		}
+		if s, isString := result.(string); isString {
+			if _, err := fmt.Fprintln(w, s); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeJSON(w, result, ""); err != nil {
+			return err
+		}
+	}
+}
+
+func writeJSON(w io.Writer, data interface{}, indent string) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	return enc.Encode(data)
+}
+
+// templateFuncs — функции, доступные в --template.
+var templateFuncs = template.FuncMap{
+	// json prints a value as compact JSON
+	"json": func(v interface{}) (string, error) {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, v, ""); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	},
+	// join joins a list with sep: {{join ", " .labels}}
+	"join": func(sep string, list []interface{}) string {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	// truncate shortens s to n characters: {{truncate 40 .title}}
+	"truncate": func(n int, s interface{}) string {
+		str := fmt.Sprint(s)
+		if s == nil {
+			str = ""
+		}
+		runes := []rune(str)
+		if n <= 3 || len(runes) <= n {
+			return str
+		}
+		return string(runes[:n-3]) + "..."
+	},
+	// timeago formats an RFC 3339 timestamp relative to now: {{timeago .updated_at}}
+	"timeago": func(ts interface{}) string {
+		s, _ := ts.(string)
+		return cliutils.FormatRelativeTime(s)
+	},
+}